@@ -0,0 +1,331 @@
+// Command gorecord works with game records in bulk, entirely offline:
+//
+//	gorecord validate [files or directories]
+//	gorecord convert -to sgf|bin [-out directory] [files or directories]
+//	gorecord score [-rules auto|area|territory] [-playouts n] [files or directories]
+//	gorecord stats [files or directories]
+//
+// Directories are searched recursively for records in the formats read by the game package, chosen by the
+// extension of the files: .sgf, .ugf, .gib, .ngf and .bin for the binary format. SGF and binary files can
+// hold several records.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/n-bravo/go-in-go/game"
+)
+
+const usage = `usage: gorecord <command> [flags] [files or directories]
+
+Commands:
+  validate  replays every record, reporting the illegal moves
+  convert   converts the records to SGF or to the binary format
+  score     counts the final position of every record and compares it with the recorded result
+  stats     prints the number of moves, the captures and the distribution of the results
+
+Run "gorecord <command> -h" for the flags of each command.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit status: 0 for success, 1 if some records are invalid
+// or could not be processed, and 2 for a wrong command line.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	commands := map[string]func(*flag.FlagSet, io.Writer, io.Writer) func([]recordFile) error{
+		"validate": validateCommand,
+		"convert":  convertCommand,
+		"score":    scoreCommand,
+		"stats":    statsCommand,
+	}
+	command, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	exec := command(flags, stdout, stderr)
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	files, err := findRecords(paths)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if err = exec(files); err != nil {
+		if !errors.Is(err, errReported) {
+			fmt.Fprintln(stderr, err)
+		}
+		return 1
+	}
+	return 0
+}
+
+// errReported is returned by the commands when the failures were already written.
+var errReported = errors.New("some records failed")
+
+// readers are the formats that can be read, by extension.
+var readers = map[string]func([]byte) ([]*namedRecord, error){
+	".sgf": readSGF,
+	".ugf": readServerRecord(game.ParseUGF),
+	".gib": readServerRecord(game.ParseGIB),
+	".ngf": readServerRecord(game.ParseNGF),
+	".bin": readBinary,
+}
+
+// recordFile is a file found by findRecords.
+type recordFile struct {
+	path string
+	rel  string //path relative to the directory where it was found, or its name if it was given explicitly
+}
+
+// findRecords returns the record files among the paths, searching the directories recursively.
+func findRecords(paths []string) ([]recordFile, error) {
+	var files []recordFile
+	for _, p := range paths {
+		err := filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if path == p && !d.IsDir() {
+				files = append(files, recordFile{path, filepath.Base(path)}) //given explicitly, even with other extensions
+				return nil
+			}
+			if _, ok := readers[strings.ToLower(filepath.Ext(path))]; ok && !d.IsDir() {
+				rel, err := filepath.Rel(p, path)
+				if err != nil {
+					return err
+				}
+				files = append(files, recordFile{path, rel})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	slices.SortStableFunc(files, func(a, b recordFile) int { return strings.Compare(a.path, b.path) })
+	return slices.CompactFunc(files, func(a, b recordFile) bool { return a.path == b.path }), nil
+}
+
+// readFile reads the records of a file, with the format given by its extension.
+func readFile(path string) ([]*namedRecord, error) {
+	read, ok := readers[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown record format", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	records, err := read(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%s: no records", path)
+	}
+	for i, r := range records {
+		r.name = path
+		if len(records) > 1 {
+			r.name = fmt.Sprintf("%s #%v", path, i+1)
+		}
+	}
+	return records, nil
+}
+
+// eachRecord calls fn with every record of the files. The files that can not be read are reported in w, and
+// make it return errReported after all the files.
+func eachRecord(files []recordFile, w io.Writer, fn func(r *namedRecord) error) error {
+	var failed bool
+	for _, f := range files {
+		records, err := readFile(f.path)
+		if err != nil {
+			fmt.Fprintln(w, err)
+			failed = true
+			continue
+		}
+		for _, r := range records {
+			if err = fn(r); err != nil {
+				return err
+			}
+		}
+	}
+	if failed {
+		return errReported
+	}
+	return nil
+}
+
+func validateCommand(flags *flag.FlagSet, stdout, stderr io.Writer) func([]recordFile) error {
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: gorecord validate [files or directories]\n\nReplays every record, reporting the illegal moves.")
+	}
+	return func(files []recordFile) error {
+		valid, invalid := 0, 0
+		err := eachRecord(files, stderr, func(r *namedRecord) error {
+			if err := r.Replay(nil); err != nil {
+				fmt.Fprintf(stdout, "%s: %v\n", r.name, err)
+				invalid++
+			} else {
+				valid++
+			}
+			return nil
+		})
+		fmt.Fprintf(stdout, "%v valid records, %v invalid\n", valid, invalid)
+		if err == nil && invalid > 0 {
+			err = errReported
+		}
+		return err
+	}
+}
+
+func convertCommand(flags *flag.FlagSet, stdout, stderr io.Writer) func([]recordFile) error {
+	to := flags.String("to", "sgf", "output format: sgf or bin")
+	out := flags.String("out", "", "directory of the converted files, with the subdirectories of the original ones. By default, next to the original ones")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: gorecord convert [flags] [files or directories]\n\n"+
+			"Converts the records, writing the ones of each file in a file with the same name and the extension of the format.\n"+
+			"Files that would overwrite a record being converted, or another converted file, are skipped.")
+		flags.PrintDefaults()
+	}
+	return func(files []recordFile) error {
+		write, ok := writers[*to]
+		if !ok {
+			return fmt.Errorf("unknown output format %q", *to)
+		}
+		var failed bool
+		sources := make(map[string]string) //converted files by target, to find the ones with the same name
+		for _, f := range files {
+			sources[f.path] = f.path //never overwritten
+		}
+		for _, f := range files {
+			path := f.path
+			records, err := readFile(path)
+			if err != nil {
+				fmt.Fprintln(stderr, err)
+				failed = true
+				continue
+			}
+			var buf bytes.Buffer
+			for _, r := range records {
+				if err = write(&buf, r); err != nil {
+					break
+				}
+			}
+			target := strings.TrimSuffix(path, filepath.Ext(path)) + "." + *to
+			if *out != "" {
+				//the subdirectories are kept, so files with the same name in different ones do not collide
+				target = filepath.Join(*out, strings.TrimSuffix(f.rel, filepath.Ext(f.rel))+"."+*to)
+			}
+			switch {
+			case err != nil:
+			case target == path:
+				err = fmt.Errorf("it is already in the %v format", *to)
+			case sources[target] == target:
+				err = fmt.Errorf("%s is one of the records being converted", target)
+			case sources[target] != "":
+				err = fmt.Errorf("%s is also converted from %s", target, sources[target])
+			default:
+				sources[target] = path
+				if err = os.MkdirAll(filepath.Dir(target), 0o755); err == nil {
+					err = os.WriteFile(target, buf.Bytes(), 0o644)
+				}
+			}
+			if err != nil {
+				fmt.Fprintf(stderr, "%s: %v\n", path, err)
+				failed = true
+				continue
+			}
+			fmt.Fprintf(stdout, "%s -> %s (%v records)\n", path, target, len(records))
+		}
+		if failed {
+			return errReported
+		}
+		return nil
+	}
+}
+
+func scoreCommand(flags *flag.FlagSet, stdout, stderr io.Writer) func([]recordFile) error {
+	rules := flags.String("rules", "auto", "counting: area, territory, or auto to use territory for Japanese and Korean rules and area otherwise")
+	playouts := flags.Int("playouts", 0, "random playouts to find the dead stones. If 0, a faster static evaluation is used")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: gorecord score [flags] [files or directories]\n\n"+
+			"Counts the final position of every record, removing the stones estimated as dead, and compares it with the\n"+
+			"recorded result. Only the results by points are compared: games won by resignation or on time are just counted.")
+		flags.PrintDefaults()
+	}
+	return func(files []recordFile) error {
+		if *rules != "auto" && *rules != "area" && *rules != "territory" {
+			return fmt.Errorf("unknown rules %q", *rules)
+		}
+		compared, differ, invalid := 0, 0, 0
+		err := eachRecord(files, stderr, func(r *namedRecord) error {
+			result, err := countResult(r.Record, *rules, *playouts)
+			if err != nil {
+				fmt.Fprintf(stdout, "%s: %v\n", r.name, err)
+				invalid++
+				return nil
+			}
+			recorded := r.Result
+			if recorded == "" {
+				recorded = "?"
+			}
+			note := ""
+			if byPoints(r.Result) {
+				compared++
+				if result != r.Result {
+					note = " (differs)"
+					differ++
+				}
+			}
+			fmt.Fprintf(stdout, "%s: recorded %s, counted %s%s\n", r.name, recorded, result, note)
+			return nil
+		})
+		fmt.Fprintf(stdout, "%v of %v results by points differ, %v invalid records\n", differ, compared, invalid)
+		if err == nil && invalid > 0 {
+			err = errReported
+		}
+		return err
+	}
+}
+
+func statsCommand(flags *flag.FlagSet, stdout, stderr io.Writer) func([]recordFile) error {
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: gorecord stats [files or directories]\n\n"+
+			"Prints the number of moves, the captures and the distribution of the results of the valid records.")
+	}
+	return func(files []recordFile) error {
+		s := newStats()
+		err := eachRecord(files, stderr, func(r *namedRecord) error {
+			if err := s.add(r.Record); err != nil {
+				fmt.Fprintf(stderr, "%s: %v\n", r.name, err)
+				s.invalid++
+			}
+			return nil
+		})
+		s.write(stdout)
+		return err
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeRecords creates a directory with two valid SGF records in a file, an invalid one in another and an UGF one.
+func writeRecords(t *testing.T) string {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.sgf": "(;SZ[5]KM[0.5]RE[B+4.5]PB[Ana]AB[ca][cb][cc][cd][ce]AW[da][db][dc][dd][de];W[];B[])\n" +
+			"(;SZ[5]RE[W+R];B[aa];W[ab];B[cc];W[ba])",
+		"games/bad.sgf": "(;SZ[5]RE[B+T];B[aa];W[aa])",
+		"games/c.ugf":   "[Header]\nSize=9\nHdcp=0,6.5\nWinner=W,C\n[Data]\nEE,B1,0\nCC,W2,0\n",
+		"notes.txt":     "not a record",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestValidate(t *testing.T) {
+	assert := assert.New(t)
+	dir := writeRecords(t)
+	var stdout, stderr bytes.Buffer
+	assert.Equal(1, run([]string{"validate", dir}, &stdout, &stderr))
+	assert.Contains(stdout.String(), filepath.Join(dir, "games", "bad.sgf")+": illegal move 2 (0, 0)")
+	assert.Contains(stdout.String(), "3 valid records, 1 invalid")
+
+	stdout.Reset()
+	assert.Equal(0, run([]string{"validate", filepath.Join(dir, "a.sgf")}, &stdout, &stderr))
+	assert.Equal("2 valid records, 0 invalid\n", stdout.String())
+
+	//illegal moves are reported in the same way in every format
+	bad := filepath.Join(dir, "games", "bad.ugf")
+	assert.NoError(os.WriteFile(bad, []byte("[Header]\nSize=9\n[Data]\nEE,B1,0\nEE,W2,0\n"), 0o644))
+	stdout.Reset()
+	assert.Equal(1, run([]string{"validate", filepath.Join(dir, "games")}, &stdout, &stderr))
+	assert.Contains(stdout.String(), bad+": illegal move 2 (4, 4)")
+	assert.Contains(stdout.String(), "1 valid records, 2 invalid")
+
+	assert.Equal(2, run([]string{"check", dir}, &stdout, &stderr))
+	assert.Equal(2, run(nil, &stdout, &stderr))
+}
+
+func TestConvert(t *testing.T) {
+	assert := assert.New(t)
+	dir := writeRecords(t)
+	out := filepath.Join(dir, "out")
+	var stdout, stderr bytes.Buffer
+	assert.Equal(0, run([]string{"convert", "-to", "bin", "-out", out, dir}, &stdout, &stderr))
+	assert.FileExists(filepath.Join(out, "a.bin"))
+	assert.FileExists(filepath.Join(out, "games", "c.bin"))
+
+	assert.Equal(0, run([]string{"convert", out}, &stdout, &stderr))
+	sgf, err := os.ReadFile(filepath.Join(out, "a.sgf"))
+	assert.NoError(err)
+	assert.Equal("(;GM[1]FF[4]SZ[5]KM[0.5]PB[Ana]RE[B+4.5]AB[ca][cb][cc][cd][ce]AW[da][db][dc][dd][de]\n;W[];B[])\n"+
+		"(;GM[1]FF[4]SZ[5]RE[W+R]\n;B[aa];W[ab];B[cc];W[ba])\n", string(sgf))
+	sgf, err = os.ReadFile(filepath.Join(out, "games", "c.sgf"))
+	assert.NoError(err)
+	assert.Contains(string(sgf), "RE[W+R]\n;B[ee];W[cg])")
+
+	//the records are not overwritten
+	assert.Equal(1, run([]string{"convert", filepath.Join(dir, "a.sgf")}, &stdout, &stderr))
+	assert.Contains(stderr.String(), "already in the sgf format")
+	assert.Equal(1, run([]string{"convert", "-to", "png", dir}, &stdout, &stderr))
+
+	//nor the converted files with the same name
+	for _, name := range []string{"a/g.sgf", "b/g.sgf", "b/g.ugf"} {
+		path := filepath.Join(dir, "in", name)
+		assert.NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(os.WriteFile(path, []byte("(;SZ[5];B[aa])"), 0o644))
+	}
+	stderr.Reset()
+	assert.Equal(1, run([]string{"convert", "-to", "bin", "-out", out, filepath.Join(dir, "in")}, &stdout, &stderr))
+	assert.FileExists(filepath.Join(out, "a", "g.bin"))
+	assert.FileExists(filepath.Join(out, "b", "g.bin"))
+	assert.Contains(stderr.String(), "g.ugf: "+filepath.Join(out, "b", "g.bin")+" is also converted from")
+	stderr.Reset()
+	assert.Equal(1, run([]string{"convert", filepath.Join(dir, "in", "b")}, &stdout, &stderr))
+	assert.Contains(stderr.String(), "is one of the records being converted")
+}
+
+func TestScore(t *testing.T) {
+	assert := assert.New(t)
+	dir := writeRecords(t)
+	var stdout, stderr bytes.Buffer
+	assert.Equal(0, run([]string{"score", filepath.Join(dir, "a.sgf")}, &stdout, &stderr))
+	assert.Contains(stdout.String(), "a.sgf #1: recorded B+4.5, counted B+4.5\n")
+	assert.Contains(stdout.String(), "0 of 1 results by points differ")
+
+	stdout.Reset()
+	assert.Equal(0, run([]string{"score", "-rules", "territory", filepath.Join(dir, "a.sgf")}, &stdout, &stderr))
+	assert.Contains(stdout.String(), "a.sgf #1: recorded B+4.5, counted B+4.5\n")
+	assert.Equal(1, run([]string{"score", "-rules", "chinese", dir}, &stdout, &stderr))
+}
+
+func TestStats(t *testing.T) {
+	assert := assert.New(t)
+	dir := writeRecords(t)
+	var stdout, stderr bytes.Buffer
+	assert.Equal(0, run([]string{"stats", dir}, &stdout, &stderr))
+	assert.Equal(`Records: 3 valid, 1 invalid
+Moves: 8 in total, 2.7 on average, from 2 to 4
+Captured stones: black 1, white 0
+Results:
+  white by resignation       2   66.7%
+  black by points            1   33.3%
+`, stdout.String())
+	assert.Contains(stderr.String(), "bad.sgf: illegal move 2")
+}
+
+func TestResultKind(t *testing.T) {
+	assert := assert.New(t)
+	for result, kind := range map[string]string{
+		"B+3.5":  "black by points",
+		"W+R":    "white by resignation",
+		"w+time": "white on time",
+		"B+F":    "black by forfeit",
+		"B+":     "black",
+		"0":      "draw",
+		"Void":   "no result",
+		"":       "unknown",
+		"?":      "unknown",
+	} {
+		assert.Equal(kind, resultKind(result), result)
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/n-bravo/go-in-go/game"
+)
+
+// namedRecord is a record with the name used to report it: the path of its file, and its number if the file
+// has several records.
+type namedRecord struct {
+	*game.Record
+	name string
+}
+
+func named(records ...*game.Record) []*namedRecord {
+	nr := make([]*namedRecord, len(records))
+	for i, r := range records {
+		nr[i] = &namedRecord{Record: r}
+	}
+	return nr
+}
+
+func readSGF(data []byte) ([]*namedRecord, error) {
+	roots, err := game.ParseSGF(string(data))
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*game.Record, len(roots))
+	for i, root := range roots {
+		if records[i], err = game.RecordFromSGF(root); err != nil {
+			return nil, err
+		}
+	}
+	return named(records...), nil
+}
+
+// readServerRecord returns a reader of a format with a game per file, like UGF. The records with illegal moves
+// are kept, so the commands report them like the ones of the other formats.
+func readServerRecord(parse func(string) (*game.Record, error)) func([]byte) ([]*namedRecord, error) {
+	return func(data []byte) ([]*namedRecord, error) {
+		r, err := parse(string(data))
+		if r == nil {
+			return nil, err
+		}
+		return named(r), nil
+	}
+}
+
+func readBinary(data []byte) ([]*namedRecord, error) {
+	var records []*game.Record
+	br := bytes.NewReader(data)
+	for br.Len() > 0 {
+		r, err := game.ReadBinaryRecord(br)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	return named(records...), nil
+}
+
+// writers are the formats that records can be converted to, by extension without the dot.
+var writers = map[string]func(io.Writer, *namedRecord) error{
+	"sgf": func(w io.Writer, r *namedRecord) error {
+		_, err := io.WriteString(w, r.SGF())
+		return err
+	},
+	"bin": func(w io.Writer, r *namedRecord) error {
+		return r.WriteBinary(w)
+	},
+}
+
+// countResult replays the record and counts its final position, in SGF notation. The stones in points
+// estimated to be owned by the opponent are removed as dead before counting.
+func countResult(r *game.Record, rules string, playouts int) (string, error) {
+	g, err := r.Game()
+	if err != nil {
+		return "", err
+	}
+	if rules == "auto" {
+		rules = "area"
+		if ru := strings.ToLower(r.Rules); strings.Contains(ru, "japan") || strings.Contains(ru, "korea") {
+			rules = "territory"
+		}
+	}
+	own := g.Estimate(game.EstimateOptions{Komi: r.Komi, Playouts: playouts}).Ownership
+	stones := g.String()
+	size := g.Size()
+	var dead []game.Coord
+	for i := range stones {
+		x, y := i/size, i%size
+		if stones[i] == 'B' && own[x][y] < 0 || stones[i] == 'W' && own[x][y] > 0 {
+			dead = append(dead, game.Coord{X: x, Y: y})
+		}
+	}
+	var s game.Score
+	if rules == "territory" {
+		s, err = g.TerritoryScore(r.Komi, dead)
+	} else {
+		s, err = g.AreaScore(r.Komi, dead)
+	}
+	if err != nil {
+		return "", err
+	}
+	return s.String(), nil
+}
+
+// byPoints tells whether the result, in SGF notation, is a win by points or a draw.
+func byPoints(result string) bool {
+	if result == "0" {
+		return true
+	}
+	if len(result) < 3 || (result[0] != 'B' && result[0] != 'W') || result[1] != '+' {
+		return false
+	}
+	_, err := strconv.ParseFloat(result[2:], 64)
+	return err == nil
+}
+
+// resultKind classifies a result in SGF notation, like "black by resignation".
+func resultKind(result string) string {
+	result = strings.ToUpper(strings.TrimSpace(result))
+	switch {
+	case result == "":
+		return "unknown"
+	case result == "0" || result == "DRAW" || result == "JIGO":
+		return "draw"
+	case result == "VOID":
+		return "no result"
+	case len(result) < 2 || (result[0] != 'B' && result[0] != 'W') || result[1] != '+':
+		return "unknown"
+	}
+	winner := "black"
+	if result[0] == 'W' {
+		winner = "white"
+	}
+	switch how := result[2:]; {
+	case how == "R" || how == "RESIGN":
+		return winner + " by resignation"
+	case how == "T" || how == "TIME":
+		return winner + " on time"
+	case how == "F" || how == "FORFEIT":
+		return winner + " by forfeit"
+	case byPoints(result):
+		return winner + " by points"
+	default:
+		return winner
+	}
+}
+
+// stats are the statistics of a set of records.
+type stats struct {
+	records       int
+	invalid       int //not counted in the other fields
+	moves         int
+	minMoves      int
+	maxMoves      int
+	blackCaptures int
+	whiteCaptures int
+	results       map[string]int
+}
+
+func newStats() *stats {
+	return &stats{results: make(map[string]int)}
+}
+
+// add replays the record and adds it to the statistics, unless it has an illegal move.
+func (s *stats) add(r *game.Record) error {
+	g, err := r.Game()
+	if err != nil {
+		return err
+	}
+	moves := len(r.Moves)
+	if s.records == 0 || moves < s.minMoves {
+		s.minMoves = moves
+	}
+	s.maxMoves = max(s.maxMoves, moves)
+	s.records++
+	s.moves += moves
+	s.blackCaptures += g.BlackCaptures
+	s.whiteCaptures += g.WhiteCaptures
+	s.results[resultKind(r.Result)]++
+	return nil
+}
+
+func (s *stats) write(w io.Writer) {
+	fmt.Fprintf(w, "Records: %v valid, %v invalid\n", s.records, s.invalid)
+	if s.records == 0 {
+		return
+	}
+	fmt.Fprintf(w, "Moves: %v in total, %.1f on average, from %v to %v\n",
+		s.moves, float64(s.moves)/float64(s.records), s.minMoves, s.maxMoves)
+	fmt.Fprintf(w, "Captured stones: black %v, white %v\n", s.blackCaptures, s.whiteCaptures)
+	fmt.Fprintln(w, "Results:")
+	kinds := make([]string, 0, len(s.results))
+	for k := range s.results {
+		kinds = append(kinds, k)
+	}
+	slices.SortFunc(kinds, func(a, b string) int {
+		if s.results[a] != s.results[b] {
+			return s.results[b] - s.results[a]
+		}
+		return strings.Compare(a, b)
+	})
+	for _, k := range kinds {
+		fmt.Fprintf(w, "  %-22s %5v  %5.1f%%\n", k, s.results[k], 100*float64(s.results[k])/float64(s.records))
+	}
+}
@@ -0,0 +1,191 @@
+// Package diagram draws Go positions as SVG or PNG images, for newsletters, chats or any other place where
+// an image of the board is more convenient than the game itself.
+//
+// Both formats are drawn by the same code on a canvas, so they look the same.
+package diagram
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+
+	"github.com/n-bravo/go-in-go/game"
+)
+
+// Columns are named with letters from the left, skipping I as usual in Go, and rows with numbers from the bottom.
+const columns = "ABCDEFGHJKLMNOPQRSTUVWXYZ"
+
+const (
+	defaultCell = 30 //pixels between lines when Options.Width is not given
+	maxSize     = 25 //the columns have letters up to Z
+)
+
+// Options are the optional parts of a diagram. The zero value draws only the board and the stones.
+type Options struct {
+	Width       int                   // Width and height of the image in pixels. 0 for 30 pixels between lines.
+	Coordinates bool                  // Draws the column letters and the row numbers around the board.
+	LastMove    bool                  // Marks the last move of the game with a circle.
+	Numbers     map[game.Coord]int    // Move numbers drawn on the stones, or on empty points for stones no longer on the board.
+	Triangles   []game.Coord          // Points marked with a triangle.
+	Labels      map[game.Coord]string // Short labels, like letters for the variations. PNG images only draw A-Z and 0-9.
+}
+
+var (
+	background = color.RGBA{220, 179, 92, 255}
+	ink        = color.RGBA{0, 0, 0, 255}
+	stoneFills = map[byte]color.RGBA{
+		'B': {20, 20, 20, 255},
+		'W': {245, 245, 245, 255},
+		'R': {200, 40, 40, 255},
+		'G': {40, 150, 60, 255},
+		'Y': {235, 200, 40, 255},
+	}
+)
+
+// canvas is implemented by each image format.
+type canvas interface {
+	rect(x, y, w, h float64, c color.RGBA)
+	line(x1, y1, x2, y2, width float64, c color.RGBA)
+	disc(cx, cy, r float64, c color.RGBA)
+	ring(cx, cy, r, width float64, c color.RGBA)
+	triangle(cx, cy, r, width float64, c color.RGBA)
+	text(cx, cy, height float64, s string, c color.RGBA)
+}
+
+// layout places the points of the board in the image.
+type layout struct {
+	size   int
+	cell   float64 //distance between lines
+	margin float64 //distance from the border of the image to the first line
+	width  float64
+}
+
+func newLayout(size int, opts Options) layout {
+	margins := 0.7 //in cells, on each side
+	if opts.Coordinates {
+		margins = 1.3
+	}
+	l := layout{size: size, cell: defaultCell}
+	if opts.Width > 0 {
+		l.cell = float64(opts.Width) / (float64(size-1) + 2*margins)
+	}
+	l.margin = l.cell * margins
+	l.width = 2*l.margin + float64(size-1)*l.cell
+	if opts.Width > 0 {
+		l.width = float64(opts.Width)
+	}
+	return l
+}
+
+// point returns the center of the point in row x and column y.
+func (l layout) point(x, y int) (float64, float64) {
+	return l.margin + float64(y)*l.cell, l.margin + float64(x)*l.cell
+}
+
+// contrast returns the color of the marks drawn on a point with the given state, from GoGame.String.
+func contrast(state byte) color.RGBA {
+	if state == 'W' || state == 'Y' || state == '*' {
+		return ink
+	}
+	return stoneFills['W']
+}
+
+// draw paints the position of the game in the canvas.
+func draw(c canvas, g *game.GoGame, opts Options) error {
+	size := g.Size()
+	if size > maxSize {
+		return fmt.Errorf("boards bigger than %v x %v can not be drawn", maxSize, maxSize)
+	}
+	l := newLayout(size, opts)
+	stones := g.String()
+	c.rect(0, 0, l.width, l.width, background)
+
+	lineWidth := max(1, l.cell/30)
+	first, _ := l.point(0, 0)
+	last, _ := l.point(0, size-1)
+	for i := 0; i < size; i++ {
+		x, y := l.point(i, i)
+		c.line(first, y, last, y, lineWidth, ink)
+		c.line(x, first, x, last, lineWidth, ink)
+	}
+	for _, h := range game.Hoshi(size) {
+		x, y := l.point(h.X, h.Y)
+		c.disc(x, y, l.cell/10+lineWidth/2, ink)
+	}
+	if opts.Coordinates {
+		for i := 0; i < size; i++ {
+			x, y := l.point(i, i)
+			for _, edge := range []float64{l.margin - 0.85*l.cell, l.margin + float64(size-1)*l.cell + 0.85*l.cell} {
+				c.text(x, edge, l.cell*0.4, string(columns[i]), ink)
+				c.text(edge, y, l.cell*0.4, strconv.Itoa(size-i), ink)
+			}
+		}
+	}
+
+	radius := l.cell * 0.48
+	for i := range stones {
+		fill, ok := stoneFills[stones[i]]
+		if !ok {
+			continue
+		}
+		x, y := l.point(i/size, i%size)
+		c.disc(x, y, radius, ink)
+		c.disc(x, y, radius-lineWidth, fill)
+	}
+
+	//marks on empty points hide the lines below them
+	mark := func(p game.Coord) (float64, float64, byte) {
+		x, y := l.point(p.X, p.Y)
+		state := stones[p.X*size+p.Y]
+		if state == '*' {
+			c.disc(x, y, l.cell*0.35, background)
+		}
+		return x, y, state
+	}
+	for p, n := range opts.Numbers {
+		if inside(p, size) {
+			x, y, state := mark(p)
+			c.text(x, y, l.cell*0.4, strconv.Itoa(n), contrast(state))
+		}
+	}
+	for p, s := range opts.Labels {
+		if inside(p, size) {
+			x, y, state := mark(p)
+			c.text(x, y, l.cell*0.45, s, contrast(state))
+		}
+	}
+	for _, p := range opts.Triangles {
+		if inside(p, size) {
+			x, y, state := mark(p)
+			c.triangle(x, y, l.cell*0.28, 2*lineWidth, contrast(state))
+		}
+	}
+	if m, ok := g.LastMove(); ok && opts.LastMove && !m.Pass {
+		x, y := l.point(m.X, m.Y)
+		c.ring(x, y, l.cell*0.22, 2*lineWidth, contrast(stones[m.X*size+m.Y]))
+	}
+	return nil
+}
+
+func inside(p game.Coord, size int) bool {
+	return p.X >= 0 && p.X < size && p.Y >= 0 && p.Y < size
+}
+
+// SVG writes the diagram of the position as an SVG image.
+func SVG(w io.Writer, g *game.GoGame, opts Options) error {
+	c := newSVGCanvas(newLayout(g.Size(), opts).width)
+	if err := draw(c, g, opts); err != nil {
+		return err
+	}
+	return c.write(w)
+}
+
+// PNG writes the diagram of the position as a PNG image.
+func PNG(w io.Writer, g *game.GoGame, opts Options) error {
+	c := newPNGCanvas(newLayout(g.Size(), opts).width)
+	if err := draw(c, g, opts); err != nil {
+		return err
+	}
+	return c.write(w)
+}
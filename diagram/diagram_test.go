@@ -0,0 +1,71 @@
+package diagram
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/n-bravo/go-in-go/game"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestGame(t *testing.T) *game.GoGame {
+	g, err := game.NewGame(9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range []game.Move{{X: 2, Y: 2}, {X: 6, Y: 6}, {X: 2, Y: 6}} {
+		if err = g.Play(m.X, m.Y, i%2 == 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return g
+}
+
+func TestSVG(t *testing.T) {
+	assert := assert.New(t)
+	g := newTestGame(t)
+	var buf bytes.Buffer
+	err := SVG(&buf, g, Options{Coordinates: true, LastMove: true, Triangles: []game.Coord{{X: 4, Y: 4}},
+		Labels: map[game.Coord]string{{X: 0, Y: 0}: "A"}, Numbers: map[game.Coord]int{{X: 2, Y: 2}: 1}})
+	assert.NoError(err)
+	svg := buf.String()
+	assert.True(strings.HasPrefix(svg, "<svg "))
+	assert.Contains(svg, `width="318"`)
+	assert.Equal(18, strings.Count(svg, "<line "))
+	assert.Contains(svg, ">J</text>")
+	assert.NotContains(svg, ">I</text>")
+	assert.Contains(svg, ">9</text>")
+	assert.Contains(svg, ">1</text>")
+	assert.Contains(svg, "<polygon ")
+	assert.Contains(svg, `fill="none" stroke="#f5f5f5"`) //last move, on a black stone
+	assert.Equal(2, strings.Count(svg, `fill="#141414"`))
+	assert.Equal(1, strings.Count(svg, `fill="#f5f5f5"/>`))
+
+	big, _ := game.NewGame(26)
+	assert.Error(SVG(&buf, big, Options{}))
+}
+
+func TestPNG(t *testing.T) {
+	assert := assert.New(t)
+	g := newTestGame(t)
+	var buf bytes.Buffer
+	assert.NoError(PNG(&buf, g, Options{Width: 200, Coordinates: true, LastMove: true}))
+	img, err := png.Decode(&buf)
+	assert.NoError(err)
+	assert.Equal(200, img.Bounds().Dx())
+	assert.Equal(200, img.Bounds().Dy())
+
+	l := newLayout(9, Options{Width: 200, Coordinates: true})
+	colorAt := func(x, y int, d float64) [3]uint32 {
+		px, py := l.point(x, y)
+		r, g, b, _ := img.At(int(px+d), int(py+d)).RGBA()
+		return [3]uint32{r >> 8, g >> 8, b >> 8}
+	}
+	assert.Equal([3]uint32{20, 20, 20}, colorAt(2, 2, 0))
+	assert.Equal([3]uint32{245, 245, 245}, colorAt(6, 6, 0))
+	assert.Equal([3]uint32{20, 20, 20}, colorAt(2, 6, 0))               //inside the last move mark
+	assert.Equal([3]uint32{245, 245, 245}, colorAt(2, 6, l.cell*0.155)) //on the mark, at 45 degrees
+	assert.Equal([3]uint32{220, 179, 92}, colorAt(4, 4, l.cell/4))
+}
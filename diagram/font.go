@@ -0,0 +1,48 @@
+package diagram
+
+// The PNG images use a tiny pixel font, as the standard library has no fonts. Each glyph is 3 x 5 pixels,
+// given row by row, where # is painted.
+const (
+	glyphWidth  = 3
+	glyphHeight = 5
+)
+
+var glyphs = map[rune]string{
+	'0': "####.##.##.####",
+	'1': ".#.##..#..#.###",
+	'2': "###..#####..###",
+	'3': "###..####..####",
+	'4': "#.##.####..#..#",
+	'5': "####..###..####",
+	'6': "####..####.####",
+	'7': "###..#..#..#..#",
+	'8': "####.#####.####",
+	'9': "####.####..####",
+	'A': ".#.#.#####.##.#",
+	'B': "##.#.###.#.###.",
+	'C': ".###..#..#...##",
+	'D': "##.#.##.##.###.",
+	'E': "####..##.#..###",
+	'F': "####..##.#..#..",
+	'G': ".###..#.##.#.##",
+	'H': "#.##.#####.##.#",
+	'I': "###.#..#..#.###",
+	'J': "..#..#..##.#.#.",
+	'K': "#.##.###.#.##.#",
+	'L': "#..#..#..#..###",
+	'M': "#.########.##.#",
+	'N': "##.#.##.##.##.#",
+	'O': ".#.#.##.##.#.#.",
+	'P': "##.#.###.#..#..",
+	'Q': ".#.#.##.###..##",
+	'R': "##.#.###.#.##.#",
+	'S': ".###...#...###.",
+	'T': "###.#..#..#..#.",
+	'U': "#.##.##.##.####",
+	'V': "#.##.##.##.#.#.",
+	'W': "#.##.########.#",
+	'X': "#.##.#.#.#.##.#",
+	'Y': "#.##.#.#..#..#.",
+	'Z': "###..#.#.#..###",
+	'-': "......###......",
+}
@@ -0,0 +1,111 @@
+package diagram
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+)
+
+// pngCanvas paints on an RGBA image, smoothing the borders of the shapes by the covered area of each pixel.
+type pngCanvas struct {
+	img *image.RGBA
+}
+
+func newPNGCanvas(width float64) *pngCanvas {
+	size := int(math.Round(width))
+	return &pngCanvas{img: image.NewRGBA(image.Rect(0, 0, size, size))}
+}
+
+// blend paints the pixel with the color, covering the given fraction of it.
+func (p *pngCanvas) blend(x, y int, c color.RGBA, coverage float64) {
+	if coverage <= 0 || !(image.Point{x, y}.In(p.img.Rect)) {
+		return
+	}
+	coverage = min(coverage, 1)
+	old := p.img.RGBAAt(x, y)
+	mix := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a)*coverage + float64(b)*(1-coverage)))
+	}
+	p.img.SetRGBA(x, y, color.RGBA{mix(c.R, old.R), mix(c.G, old.G), mix(c.B, old.B), 255})
+}
+
+// paint blends every pixel in the box with the coverage returned by fn for the center of the pixel.
+func (p *pngCanvas) paint(x0, y0, x1, y1 float64, c color.RGBA, fn func(x, y float64) float64) {
+	for py := int(math.Floor(y0)); py <= int(math.Ceil(y1)); py++ {
+		for px := int(math.Floor(x0)); px <= int(math.Ceil(x1)); px++ {
+			p.blend(px, py, c, fn(float64(px)+0.5, float64(py)+0.5))
+		}
+	}
+}
+
+func (p *pngCanvas) rect(x, y, w, h float64, c color.RGBA) {
+	p.paint(x, y, x+w, y+h, c, func(px, py float64) float64 {
+		return min(px-x+0.5, x+w-px+0.5, py-y+0.5, y+h-py+0.5)
+	})
+}
+
+func (p *pngCanvas) line(x1, y1, x2, y2, width float64, c color.RGBA) {
+	half := width / 2
+	p.paint(min(x1, x2)-half, min(y1, y2)-half, max(x1, x2)+half, max(y1, y2)+half, c, func(px, py float64) float64 {
+		return half - segmentDistance(px, py, x1, y1, x2, y2) + 0.5
+	})
+}
+
+func (p *pngCanvas) disc(cx, cy, r float64, c color.RGBA) {
+	p.paint(cx-r, cy-r, cx+r, cy+r, c, func(px, py float64) float64 {
+		return r - math.Hypot(px-cx, py-cy) + 0.5
+	})
+}
+
+func (p *pngCanvas) ring(cx, cy, r, width float64, c color.RGBA) {
+	outer := r + width/2
+	p.paint(cx-outer, cy-outer, cx+outer, cy+outer, c, func(px, py float64) float64 {
+		return width/2 - math.Abs(math.Hypot(px-cx, py-cy)-r) + 0.5
+	})
+}
+
+func (p *pngCanvas) triangle(cx, cy, r, width float64, c color.RGBA) {
+	v := triangleVertices(cx, cy, r)
+	for i := range v {
+		j := (i + 1) % len(v)
+		p.line(v[i][0], v[i][1], v[j][0], v[j][1], width, c)
+	}
+}
+
+// text draws the string with the pixel font, scaled to the height and centered in (cx, cy).
+func (p *pngCanvas) text(cx, cy, height float64, s string, c color.RGBA) {
+	scale := max(1, math.Round(height/glyphHeight))
+	w := float64(len(s)*(glyphWidth+1)-1) * scale
+	x0, y0 := math.Round(cx-w/2), math.Round(cy-glyphHeight*scale/2)
+	for i, r := range s {
+		g, ok := glyphs[r]
+		if !ok {
+			continue
+		}
+		for row := 0; row < glyphHeight; row++ {
+			for col := 0; col < glyphWidth; col++ {
+				if g[row*glyphWidth+col] == '#' {
+					x := x0 + float64(i*(glyphWidth+1)+col)*scale
+					y := y0 + float64(row)*scale
+					p.rect(x, y, scale, scale, c)
+				}
+			}
+		}
+	}
+}
+
+func (p *pngCanvas) write(w io.Writer) error {
+	return png.Encode(w, p.img)
+}
+
+// segmentDistance returns the distance from (px, py) to the segment from (x1, y1) to (x2, y2).
+func segmentDistance(px, py, x1, y1, x2, y2 float64) float64 {
+	dx, dy := x2-x1, y2-y1
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = max(0, min(1, ((px-x1)*dx+(py-y1)*dy)/l))
+	}
+	return math.Hypot(px-x1-t*dx, py-y1-t*dy)
+}
@@ -0,0 +1,66 @@
+package diagram
+
+import (
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+	"math"
+	"strings"
+)
+
+// svgCanvas collects the elements of an SVG image.
+type svgCanvas struct {
+	sb    strings.Builder
+	width float64
+}
+
+func newSVGCanvas(width float64) *svgCanvas {
+	return &svgCanvas{width: width}
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func (s *svgCanvas) rect(x, y, w, h float64, c color.RGBA) {
+	fmt.Fprintf(&s.sb, "<rect x=\"%.1f\" y=\"%.1f\" width=\"%.1f\" height=\"%.1f\" fill=\"%s\"/>\n", x, y, w, h, hex(c))
+}
+
+func (s *svgCanvas) line(x1, y1, x2, y2, width float64, c color.RGBA) {
+	fmt.Fprintf(&s.sb, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"%s\" stroke-width=\"%.1f\" stroke-linecap=\"square\"/>\n",
+		x1, y1, x2, y2, hex(c), width)
+}
+
+func (s *svgCanvas) disc(cx, cy, r float64, c color.RGBA) {
+	fmt.Fprintf(&s.sb, "<circle cx=\"%.1f\" cy=\"%.1f\" r=\"%.1f\" fill=\"%s\"/>\n", cx, cy, r, hex(c))
+}
+
+func (s *svgCanvas) ring(cx, cy, r, width float64, c color.RGBA) {
+	fmt.Fprintf(&s.sb, "<circle cx=\"%.1f\" cy=\"%.1f\" r=\"%.1f\" fill=\"none\" stroke=\"%s\" stroke-width=\"%.1f\"/>\n", cx, cy, r, hex(c), width)
+}
+
+func (s *svgCanvas) triangle(cx, cy, r, width float64, c color.RGBA) {
+	points := make([]string, 3)
+	for i, v := range triangleVertices(cx, cy, r) {
+		points[i] = fmt.Sprintf("%.1f,%.1f", v[0], v[1])
+	}
+	fmt.Fprintf(&s.sb, "<polygon points=\"%s\" fill=\"none\" stroke=\"%s\" stroke-width=\"%.1f\"/>\n", strings.Join(points, " "), hex(c), width)
+}
+
+func (s *svgCanvas) text(cx, cy, height float64, text string, c color.RGBA) {
+	fmt.Fprintf(&s.sb, "<text x=\"%.1f\" y=\"%.1f\" font-size=\"%.1f\" fill=\"%s\">%s</text>\n", cx, cy, height*1.3, hex(c), html.EscapeString(text))
+}
+
+func (s *svgCanvas) write(w io.Writer) error {
+	size := math.Round(s.width)
+	_, err := fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%v\" height=\"%v\" viewBox=\"0 0 %v %v\" "+
+		"font-family=\"sans-serif\" text-anchor=\"middle\" dominant-baseline=\"central\">\n%s</svg>\n", size, size, size, size, s.sb.String())
+	return err
+}
+
+// triangleVertices returns the vertices of the equilateral triangle pointing up inscribed in the circle.
+func triangleVertices(cx, cy, r float64) [3][2]float64 {
+	dx, dy := r*math.Sqrt(3)/2, r/2
+	return [3][2]float64{{cx, cy - r}, {cx + dx, cy + dy}, {cx - dx, cy + dy}}
+}
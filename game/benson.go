@@ -0,0 +1,129 @@
+package game
+
+import "slices"
+
+// bensonRegion is a maximal connected set of points without stones of the player analysed.
+type bensonRegion struct {
+	points []*Point
+	border map[int]bool //ids of the player chains around the region
+	vital  map[int]bool //ids of the chains the region is vital to: all its free points are liberties of the chain
+}
+
+// PassAlive runs Benson's algorithm for the player, finding its unconditionally alive chains:
+// the chains that cannot be captured even if the player always passes.
+// It returns the stones of each alive chain, and the regions controlled by them, where the opponent cannot live.
+func (g *GoGame) PassAlive(black bool) (chains [][]Coord, regions [][]Coord) {
+	b := g.board
+	state := WHITE
+	if black {
+		state = BLACK
+	}
+	alive := make(map[int]bool)
+	for id, c := range b.chains {
+		if (c.state == BLACK) == black {
+			alive[id] = true
+		}
+	}
+	healthy := bensonRegions(b, state)
+	for changed := true; changed; {
+		changed = false
+		//remove the chains with less than two vital regions
+		for id := range alive {
+			vitals := 0
+			for _, r := range healthy {
+				if r.vital[id] {
+					vitals += 1
+				}
+			}
+			if vitals < 2 {
+				delete(alive, id)
+				changed = true
+			}
+		}
+		//remove the regions touching a removed chain
+		for i := 0; i < len(healthy); i++ {
+			for id := range healthy[i].border {
+				if !alive[id] {
+					healthy = append(healthy[:i], healthy[i+1:]...)
+					i -= 1
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	chains = make([][]Coord, 0)
+	seen := make(map[int]bool)
+	for x := range b.field {
+		for y := range b.field[x] {
+			id := b.field[x][y].chainId()
+			if b.field[x][y].State == state && alive[id] && !seen[id] {
+				seen[id] = true
+				chains = append(chains, coordsOf(b.chains[id].stones()))
+			}
+		}
+	}
+	regions = make([][]Coord, 0)
+	for _, r := range healthy {
+		if len(r.vital) > 0 {
+			regions = append(regions, coordsOf(r.points))
+		}
+	}
+	return chains, regions
+}
+
+// bensonRegions splits the points without stones of color state in connected regions.
+func bensonRegions(b *board, state pointStateType) []*bensonRegion {
+	seen := make(map[*Point]bool)
+	regions := make([]*bensonRegion, 0)
+	for x := range b.field {
+		for y := range b.field[x] {
+			start := &b.field[x][y]
+			if start.State == state || seen[start] {
+				continue
+			}
+			r := &bensonRegion{border: make(map[int]bool), vital: make(map[int]bool)}
+			seen[start] = true
+			pending := []*Point{start}
+			for len(pending) > 0 {
+				p := pending[len(pending)-1]
+				pending = pending[:len(pending)-1]
+				r.points = append(r.points, p)
+				for _, n := range p.neighbords {
+					if n.State == state {
+						r.border[n.chainId()] = true
+					} else if !seen[n] {
+						seen[n] = true
+						pending = append(pending, n)
+					}
+				}
+			}
+			for id := range r.border {
+				r.vital[id] = true
+				for _, p := range r.points {
+					if p.State == FREE && !b.chains[id].libs[p] {
+						delete(r.vital, id)
+						break
+					}
+				}
+			}
+			regions = append(regions, r)
+		}
+	}
+	return regions
+}
+
+// coordsOf returns the positions of the points sorted by row and column.
+func coordsOf(points []*Point) []Coord {
+	coords := make([]Coord, len(points))
+	for i, p := range points {
+		coords[i] = Coord{X: p.X, Y: p.Y}
+	}
+	slices.SortFunc(coords, func(a, b Coord) int {
+		if a.X != b.X {
+			return a.X - b.X
+		}
+		return a.Y - b.Y
+	})
+	return coords
+}
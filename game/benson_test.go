@@ -0,0 +1,62 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPassAliveTwoEyes(t *testing.T) {
+	assert := assert.New(t)
+	g := newGameFromRows(t,
+		"*B*B*",
+		"BBBBB",
+		"*****",
+		"**W**",
+		"*****",
+	)
+	chains, regions := g.PassAlive(true)
+	assert.Equal([][]Coord{{{0, 1}, {0, 3}, {1, 0}, {1, 1}, {1, 2}, {1, 3}, {1, 4}}}, chains)
+	assert.Equal([][]Coord{{{0, 0}}, {{0, 2}}, {{0, 4}}}, regions)
+	chains, regions = g.PassAlive(false)
+	assert.Empty(chains)
+	assert.Empty(regions)
+}
+
+func TestPassAliveOneEyeIsNotAlive(t *testing.T) {
+	assert := assert.New(t)
+	g := newGameFromRows(t,
+		"*B*W*",
+		"BBBW*",
+		"WWWW*",
+		"*****",
+		"*****",
+	)
+	chains, regions := g.PassAlive(true)
+	assert.Empty(chains)
+	assert.Empty(regions)
+}
+
+func TestPassAliveSharedEyes(t *testing.T) {
+	//two black chains sharing an eye with a white stone dead inside
+	assert := assert.New(t)
+	g := newGameFromRows(t,
+		"*B*B*",
+		"*B*B*",
+		"*BWB*",
+		"*B*B*",
+		"*B*B*",
+	)
+	chains, _ := g.PassAlive(true)
+	assert.Len(chains, 2)
+	g = newGameFromRows(t,
+		"B*B**",
+		"*BB**",
+		"BB***",
+		"*****",
+		"*****",
+	)
+	chains, regions := g.PassAlive(true)
+	assert.Equal([][]Coord{{{0, 0}}, {{0, 2}, {1, 1}, {1, 2}, {2, 0}, {2, 1}}}, chains)
+	assert.Equal([][]Coord{{{0, 1}}, {{1, 0}}}, regions)
+}
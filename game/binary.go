@@ -0,0 +1,295 @@
+package game
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+)
+
+// The binary record format stores a game in a few bytes, for large archives:
+//
+//	byte     version, currently 1
+//	byte     board size, up to 25
+//	varint   komi, in quarters of a point
+//	strings  rules, black player, white player, black rank, white rank and result, each one as its length
+//	         in an uvarint followed by its bytes
+//	uvarint  number of setup stones, followed by the stones encoded like moves
+//	moves    until the end mark
+//
+// Each move is the value 2*v + c, where v is 0 for a pass, 1 + x*size + y for a stone, or size*size + 1 for
+// the end mark, and c is 1 for white. It takes one byte in boards up to 11x11, and two bytes, big-endian,
+// in bigger boards. Several records can be stored one after the other in the same stream.
+const binaryVersion = 1
+
+const maxBinarySize = 25
+
+// RecordWriter writes a record in the binary format, one move at a time.
+type RecordWriter struct {
+	w     io.Writer
+	size  int
+	width int //bytes per move
+	err   error
+}
+
+// NewRecordWriter writes the header of the record: everything but the moves, which are written with
+// WriteMove. Close must be called after the last move.
+func NewRecordWriter(w io.Writer, header *Record) (*RecordWriter, error) {
+	if header.Size < 2 || header.Size > maxBinarySize {
+		return nil, fmt.Errorf("invalid board size %v, must be between 2 and %v", header.Size, maxBinarySize)
+	}
+	quarters := header.Komi * 4
+	if quarters != math.Trunc(quarters) || math.Abs(quarters) > math.MaxInt32 {
+		return nil, fmt.Errorf("komi %v can not be stored, it must be a multiple of 0.25", header.Komi)
+	}
+	buf := []byte{binaryVersion, byte(header.Size)}
+	buf = binary.AppendVarint(buf, int64(quarters))
+	for _, s := range []string{header.Rules, header.BlackPlayer, header.WhitePlayer, header.BlackRank, header.WhiteRank, header.Result} {
+		if len(s) > math.MaxUint16 {
+			return nil, fmt.Errorf("text of %v bytes can not be stored", len(s))
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(s)))
+		buf = append(buf, s...)
+	}
+	rw := &RecordWriter{w: w, size: header.Size, width: moveWidth(header.Size)}
+	buf = binary.AppendUvarint(buf, uint64(len(header.BlackSetup)+len(header.WhiteSetup)))
+	for i, c := range slices.Concat(header.BlackSetup, header.WhiteSetup) {
+		m := RecordMove{Move: Move{X: c.X, Y: c.Y}, Black: i < len(header.BlackSetup)}
+		var err error
+		if buf, err = rw.appendMove(buf, m); err != nil {
+			return nil, fmt.Errorf("invalid setup stone: %v", err)
+		}
+	}
+	if _, err := w.Write(buf); err != nil {
+		return nil, err
+	}
+	return rw, nil
+}
+
+// WriteMove writes the next move of the record.
+func (rw *RecordWriter) WriteMove(m RecordMove) error {
+	if rw.err != nil {
+		return rw.err
+	}
+	buf, err := rw.appendMove(make([]byte, 0, 2), m)
+	if err != nil {
+		return err
+	}
+	_, rw.err = rw.w.Write(buf)
+	return rw.err
+}
+
+// Close writes the end mark of the record. It does not close the underlying writer.
+func (rw *RecordWriter) Close() error {
+	if rw.err != nil {
+		return rw.err
+	}
+	_, rw.err = rw.w.Write(rw.appendValue(nil, 2*(rw.size*rw.size+1)))
+	if rw.err == nil {
+		rw.err = errors.New("record writer is closed")
+		return nil
+	}
+	return rw.err
+}
+
+func (rw *RecordWriter) appendMove(buf []byte, m RecordMove) ([]byte, error) {
+	v := 0
+	if !m.Pass {
+		if m.X < 0 || m.X >= rw.size || m.Y < 0 || m.Y >= rw.size {
+			return nil, fmt.Errorf("invalid position (%v, %v)", m.X, m.Y)
+		}
+		v = 1 + m.X*rw.size + m.Y
+	}
+	v *= 2
+	if !m.Black {
+		v += 1
+	}
+	return rw.appendValue(buf, v), nil
+}
+
+func (rw *RecordWriter) appendValue(buf []byte, v int) []byte {
+	if rw.width == 1 {
+		return append(buf, byte(v))
+	}
+	return binary.BigEndian.AppendUint16(buf, uint16(v))
+}
+
+// moveWidth returns the bytes taken by each move in boards of the size.
+func moveWidth(size int) int {
+	if 2*(size*size+1) <= math.MaxUint8 {
+		return 1
+	}
+	return 2
+}
+
+// RecordReader reads a record in the binary format, one move at a time.
+type RecordReader struct {
+	r      io.ByteReader
+	header Record
+	width  int
+	done   bool
+}
+
+// NewRecordReader reads the header of a record. It returns io.EOF if the stream has no more records.
+// If r is an io.ByteReader, like a bufio.Reader, nothing is read after the end of the record, so the next
+// record of the stream can be read with another RecordReader.
+func NewRecordReader(r io.Reader) (*RecordReader, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	rr := &RecordReader{r: br}
+	version, err := br.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if version != binaryVersion {
+		return nil, fmt.Errorf("unsupported record version %v", version)
+	}
+	size, err := br.ReadByte()
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	if size < 2 || size > maxBinarySize {
+		return nil, fmt.Errorf("invalid board size %v", size)
+	}
+	h := &rr.header
+	h.Size = int(size)
+	rr.width = moveWidth(h.Size)
+	quarters, err := binary.ReadVarint(br)
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	h.Komi = float64(quarters) / 4
+	for _, s := range []*string{&h.Rules, &h.BlackPlayer, &h.WhitePlayer, &h.BlackRank, &h.WhiteRank, &h.Result} {
+		if *s, err = rr.readString(); err != nil {
+			return nil, err
+		}
+	}
+	stones, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	for range stones {
+		m, end, err := rr.readMove()
+		if err != nil {
+			return nil, err
+		}
+		if end || m.Pass {
+			return nil, fmt.Errorf("invalid setup stone")
+		}
+		if m.Black {
+			h.BlackSetup = append(h.BlackSetup, Coord{m.X, m.Y})
+		} else {
+			h.WhiteSetup = append(h.WhiteSetup, Coord{m.X, m.Y})
+		}
+	}
+	return rr, nil
+}
+
+// Header returns the record without its moves.
+func (rr *RecordReader) Header() Record {
+	return rr.header
+}
+
+// Next returns the next move of the record, or io.EOF after the last one.
+func (rr *RecordReader) Next() (RecordMove, error) {
+	if rr.done {
+		return RecordMove{}, io.EOF
+	}
+	m, end, err := rr.readMove()
+	if err != nil {
+		return RecordMove{}, err
+	}
+	if end {
+		rr.done = true
+		return RecordMove{}, io.EOF
+	}
+	return m, nil
+}
+
+func (rr *RecordReader) readMove() (m RecordMove, end bool, err error) {
+	v := 0
+	for range rr.width {
+		b, err := rr.r.ReadByte()
+		if err != nil {
+			return RecordMove{}, false, unexpectedEOF(err)
+		}
+		v = v<<8 | int(b)
+	}
+	size := rr.header.Size
+	m.Black = v%2 == 0
+	v /= 2
+	switch {
+	case v == 0:
+		m.Pass = true
+	case v <= size*size:
+		m.X, m.Y = (v-1)/size, (v-1)%size
+	case v == size*size+1 && m.Black:
+		return RecordMove{}, true, nil
+	default:
+		return RecordMove{}, false, fmt.Errorf("invalid move value %v", v)
+	}
+	return m, false, nil
+}
+
+func (rr *RecordReader) readString() (string, error) {
+	n, err := binary.ReadUvarint(rr.r)
+	if err != nil {
+		return "", unexpectedEOF(err)
+	}
+	if n > math.MaxUint16 {
+		return "", fmt.Errorf("invalid string length %v", n)
+	}
+	buf := make([]byte, n)
+	for i := range buf {
+		if buf[i], err = rr.r.ReadByte(); err != nil {
+			return "", unexpectedEOF(err)
+		}
+	}
+	return string(buf), nil
+}
+
+// unexpectedEOF reports the end of the stream in the middle of a record as an error.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// WriteBinary writes the whole record in the binary format.
+func (r *Record) WriteBinary(w io.Writer) error {
+	rw, err := NewRecordWriter(w, r)
+	if err != nil {
+		return err
+	}
+	for _, m := range r.Moves {
+		if err = rw.WriteMove(m); err != nil {
+			return err
+		}
+	}
+	return rw.Close()
+}
+
+// ReadBinaryRecord reads a whole record in the binary format.
+func ReadBinaryRecord(r io.Reader) (*Record, error) {
+	rr, err := NewRecordReader(r)
+	if err != nil {
+		return nil, err
+	}
+	record := rr.Header()
+	for {
+		m, err := rr.Next()
+		if err == io.EOF {
+			return &record, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		record.Moves = append(record.Moves, m)
+	}
+}
@@ -0,0 +1,120 @@
+package game
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const binaryTestSGF = `(;GM[1]FF[4]SZ[19]KM[6.5]RU[Japanese]PB[Ana]PW[Bruno \] Díaz]BR[3k]WR[2k]RE[W+R]AB[pd][dp]
+;W[dd];B[pp];W[];B[qc]
+;W[qd])`
+
+func TestBinaryRecord(t *testing.T) {
+	assert := assert.New(t)
+	roots, err := ParseSGF(binaryTestSGF)
+	assert.NoError(err)
+	r, err := RecordFromSGF(roots[0])
+	assert.NoError(err)
+	assert.Equal("Japanese", r.Rules)
+	assert.Equal("Bruno ] Díaz", r.WhitePlayer)
+
+	var buf bytes.Buffer
+	assert.NoError(r.WriteBinary(&buf))
+	header := 2 + 1 + 1 + len("Japanese") + 1 + len("Ana") + 1 + len("Bruno ] Díaz") + 1 + 2 + 1 + 2 + 1 + 3 + 1 + 2*2
+	assert.Equal(header+2*len(r.Moves)+2, buf.Len())
+	read, err := ReadBinaryRecord(&buf)
+	assert.NoError(err)
+	assert.Equal(r, read)
+
+	//the SGF of the record is read back to the same record
+	roots, err = ParseSGF(r.SGF())
+	assert.NoError(err)
+	again, err := RecordFromSGF(roots[0])
+	assert.NoError(err)
+	assert.Equal(r, again)
+
+	//small boards take one byte per move
+	small := &Record{Size: 9, Komi: -0.25, Moves: []RecordMove{{Move: Move{X: 8, Y: 8}, Black: true}, {Move: Move{Pass: true}}}}
+	buf.Reset()
+	assert.NoError(small.WriteBinary(&buf))
+	assert.Equal(2+1+6+1+2+1, buf.Len())
+	read, err = ReadBinaryRecord(&buf)
+	assert.NoError(err)
+	assert.Equal(small, read)
+}
+
+func TestBinaryStream(t *testing.T) {
+	assert := assert.New(t)
+	records := []*Record{
+		{Size: 5, Moves: []RecordMove{{Move: Move{X: 1, Y: 2}, Black: true}}},
+		{Size: 13, Komi: 7.5, WhiteSetup: []Coord{{3, 3}}},
+	}
+	var buf bytes.Buffer
+	for _, r := range records {
+		w, err := NewRecordWriter(&buf, r)
+		assert.NoError(err)
+		for _, m := range r.Moves {
+			assert.NoError(w.WriteMove(m))
+		}
+		assert.NoError(w.Close())
+		assert.Error(w.WriteMove(RecordMove{}))
+	}
+	in := bufio.NewReader(&buf)
+	for _, r := range records {
+		rr, err := NewRecordReader(in)
+		assert.NoError(err)
+		h := rr.Header()
+		assert.Equal(r.Size, h.Size)
+		assert.Equal(r.WhiteSetup, h.WhiteSetup)
+		for _, m := range r.Moves {
+			next, err := rr.Next()
+			assert.NoError(err)
+			assert.Equal(m, next)
+		}
+		_, err = rr.Next()
+		assert.Equal(io.EOF, err)
+	}
+	_, err := NewRecordReader(in)
+	assert.Equal(io.EOF, err)
+}
+
+func TestBinaryErrors(t *testing.T) {
+	assert := assert.New(t)
+	var buf bytes.Buffer
+	assert.Error((&Record{Size: 19, Komi: 0.3}).WriteBinary(&buf))
+	assert.Error((&Record{Size: 26}).WriteBinary(&buf))
+	assert.Error((&Record{Size: 5, Moves: []RecordMove{{Move: Move{X: 5}}}}).WriteBinary(&buf))
+
+	buf.Reset()
+	assert.NoError((&Record{Size: 19, Moves: []RecordMove{{Move: Move{X: 3, Y: 3}, Black: true}}}).WriteBinary(&buf))
+	data := buf.Bytes()
+	_, err := ReadBinaryRecord(bytes.NewReader(data[:len(data)-1]))
+	assert.ErrorIs(err, io.ErrUnexpectedEOF)
+	_, err = ReadBinaryRecord(bytes.NewReader(append([]byte{2}, data[1:]...)))
+	assert.ErrorContains(err, "version")
+}
+
+func TestRecordFromGame(t *testing.T) {
+	assert := assert.New(t)
+	g, _ := NewGame(9)
+	assert.NoError(g.Setup(2, 2, true))
+	assert.NoError(g.Play(4, 4, true))
+	assert.NoError(g.Play(4, 5, false))
+	assert.NoError(g.Pass(true))
+	r, err := RecordFromGame(g)
+	assert.NoError(err)
+	assert.Equal([]Coord{{2, 2}}, r.BlackSetup)
+	assert.Equal(g.History(), r.Moves)
+	replayed, err := r.Game()
+	assert.NoError(err)
+	assert.Equal(g.String(), replayed.String())
+	assert.Equal(g.History(), replayed.History())
+
+	m, _ := NewMultiColorGame(9, 3)
+	_, err = RecordFromGame(m)
+	assert.Error(err)
+}
@@ -2,20 +2,30 @@ package game
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 )
 
 type board struct {
-	size       int //board is size x size
-	field      [][]Point
-	prevField  string
-	chains     map[int]*chain
-	prevChains string
+	size        int //board is size x size
+	field       [][]Point
+	prevField   string
+	chains      map[int]*chain
+	prevChains  string
+	lastChainId int //last id given to a chain. ids are never reused
+	topology    Topology
+	geo         *fastGeometry //neighbours and diagonals of the topology by flat index, shared by the clones
 }
 
-func newBoard(s int) (*board, error) {
-	board := board{size: s}
+func newBoard(s int, t Topology) (*board, error) {
+	return newBoardOf(s, t, geometryFor(s, t)), nil
+}
+
+// newBoardOf creates an empty board whose topology has the geometry geo, without computing it again.
+func newBoardOf(s int, t Topology, geo *fastGeometry) *board {
+	board := board{size: s, topology: t, geo: geo}
 	board.chains = make(map[int]*chain)
 	board.field = make([][]Point, s)
 	board.prevField = strings.Repeat("*", s*s)
@@ -28,7 +38,59 @@ func newBoard(s int) (*board, error) {
 			board.field[i][j].Init(&board, i, j)
 		}
 	}
-	return &board, nil
+	return &board
+}
+
+func (b *board) clone() *board {
+	c := newBoardOf(b.size, b.topology, b.geo)
+	c.prevField = b.prevField
+	c.prevChains = b.prevChains
+	c.lastChainId = b.lastChainId
+	for x := range b.field {
+		for y := range b.field[x] {
+			c.field[x][y].State = b.field[x][y].State
+		}
+	}
+	for id, ch := range b.chains {
+		nc := chain{id: ch.id, state: ch.state, board: c, liberties: ch.liberties, libs: make(map[*Point]bool)}
+		for p := ch.first; p != nil; p = p.next {
+			nc.link(&c.field[p.X][p.Y])
+		}
+		for l := range ch.libs {
+			nc.libs[&c.field[l.X][l.Y]] = true
+		}
+		c.chains[id] = &nc
+	}
+	return c
+}
+
+// rebuildChains recreates every chain from the stones of the field, after setting the states of the points
+// directly. The new position is also the checkpoint restored by rollBack.
+func (b *board) rebuildChains() {
+	b.chains = make(map[int]*chain)
+	for x := range b.field {
+		for y := range b.field[x] {
+			b.field[x][y].unlink()
+		}
+	}
+	for _, gr := range floodFillGroups(b) {
+		c := &chain{id: b.newChainId(), state: gr.state, board: b}
+		stones := slices.Collect(maps.Keys(gr.points))
+		slices.SortFunc(stones, func(p1, p2 *Point) int {
+			return (p1.X*b.size + p1.Y) - (p2.X*b.size + p2.Y)
+		})
+		for _, p := range stones {
+			c.link(p)
+		}
+		c.updateLiberties()
+		b.chains[c.id] = c
+	}
+	b.checkpoint()
+}
+
+func (b *board) newChainId() int {
+	b.lastChainId += 1
+	return b.lastChainId
 }
 
 func (b board) String() string {
@@ -59,19 +121,15 @@ func (b *board) rollBack() error {
 	//restore field
 	for i := 0; i < b.size; i++ {
 		for j := 0; j < b.size; j++ {
-			prevState := b.prevField[b.size*i+j]
-			switch prevState {
-			case 'B':
-				b.field[i][j].State = BLACK
-			case 'W':
-				b.field[i][j].State = WHITE
-			default:
-				b.field[i][j].State = FREE
-			}
+			b.field[i][j].State = pointStateType(strings.IndexByte(stateLetters, b.prevField[b.size*i+j]))
+			b.field[i][j].unlink()
 		}
 	}
 	//restore chains
 	b.chains = make(map[int]*chain)
+	if b.prevChains == "" {
+		return nil
+	}
 	chainStrSlc := strings.Split(b.prevChains, "%")
 	for _, chainStr := range chainStrSlc {
 		data := strings.Split(chainStr, "-")
@@ -83,7 +141,11 @@ func (b *board) rollBack() error {
 		if err != nil {
 			return err
 		}
-		c := chain{id: id, isBlack: data[0] == "1", liberties: liberties, points: make([]*Point, 0)}
+		state, err := strconv.Atoi(data[0])
+		if err != nil {
+			return err
+		}
+		c := chain{id: id, state: pointStateType(state), board: b, liberties: liberties}
 		pxy := strings.Split(data[3], "|")
 		for _, p := range pxy {
 			xy := strings.Split(p, ",")
@@ -95,53 +157,53 @@ func (b *board) rollBack() error {
 			if err != nil {
 				return err
 			}
-			c.points = append(c.points, &b.field[x][y])
-			b.field[x][y].chainId = c.id
+			c.link(&b.field[x][y])
 		}
+		c.updateLiberties()
 		b.chains[c.id] = &c
 	}
 	return nil
 }
 
-func (b *board) play(x, y int, black bool) (int, error) { //black is true if the play is from black stones player
+// play places a stone of the color in state, and returns the stones captured of each color.
+func (b *board) play(x, y int, state pointStateType) (map[pointStateType]int, error) {
 	if x < 0 || x > (b.size-1) || y < 0 || y > (b.size-1) {
-		return 0, fmt.Errorf("invalid position (%v, %v)", x, y)
+		return nil, fmt.Errorf("invalid position (%v, %v)", x, y)
 	}
-	err := b.field[x][y].play(black)
+	err := b.field[x][y].play(state)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	captured, err := b.check(black, x, y)
+	captured, err := b.check(state, x, y)
 	if err != nil {
 		b.field[x][y].free()
-		return 0, err
+		return nil, err
 	}
 	b.checkpoint()
 	return captured, nil
 }
 
 func (b *board) deleteChain(cid int) (captured int) {
-	captured = len(b.chains[cid].points)
+	captured = b.chains[cid].count
 	b.chains[cid].free()
 	delete(b.chains, cid)
 	return
 }
 
-func (b *board) check(black bool, x int, y int) (int, error) {
-	captured := 0
-	for cid := range b.chains {
-		if b.chains[cid].isBlack != black && b.chains[cid].liberties == 0 {
-			n := b.deleteChain(cid)
-			captured += n
+func (b *board) check(state pointStateType, x int, y int) (map[pointStateType]int, error) {
+	captured := make(map[pointStateType]int)
+	for _, n := range b.field[x][y].neighbords {
+		if c := n.chain(); c != nil && c.state != state && c.liberties == 0 {
+			captured[c.state] += b.deleteChain(c.id)
 		}
 	}
-	if b.chains[b.field[x][y].chainId].liberties == 0 {
+	if b.field[x][y].chain().liberties == 0 {
 		//self-captured, rollback and throw error
 		err := b.rollBack()
 		if err != nil {
 			panic(err)
 		}
-		return 0, fmt.Errorf("error self-capture forbidden")
+		return nil, fmt.Errorf("error self-capture forbidden")
 	}
 	return captured, nil
 }
@@ -6,84 +6,151 @@ import (
 	"strings"
 )
 
+// chain is a group of connected stones of the same player.
+// Its ids are unique during the whole game, and its liberties are updated on every move instead of recomputed.
+//
+// The stones form a union-find tree: each one points to a parent stone of the chain, up to the root, which
+// keeps the chain. Merging two chains only links the root of the smaller one under the other, without
+// relabelling their stones, so the trees stay shallow. The stones are also linked in a list, in the order
+// they joined the chain, to go through them.
+//
+// Union by size is enough on its own: a root is only linked under the root of a chain at least as big, so the
+// depth of a stone grows by one only when its chain doubles at least, and it is never deeper than log2 of the
+// stones of the board. Finding the chain of a stone does not compress the paths, so it never writes, and the
+// board can be read from several goroutines.
 type chain struct {
 	id        int
-	isBlack   bool
+	state     pointStateType //color of the stones
 	board     *board
-	points    []*Point
-	liberties int
+	root      *Point //root of the union-find tree of the stones
+	first     *Point //list of stones, in joining order
+	last      *Point
+	count     int             //number of stones
+	libs      map[*Point]bool //free points next to the chain
+	liberties int             //always len(libs)
 }
 
 func NewChain(id int, p *Point) (*chain, error) {
-	l := 0
+	libs := make(map[*Point]bool)
 	for _, n := range p.neighbords {
 		if n.State == p.State {
 			return nil, fmt.Errorf("cannot create a new chain in (%v, %v)", p.X, p.Y)
 		}
 		if n.State == FREE {
-			l += 1
+			libs[n] = true
 		}
 	}
-	c := chain{id: id, isBlack: p.State == BLACK, board: p.board, points: make([]*Point, 1), liberties: l}
-	c.points[0] = p
+	c := chain{id: id, state: p.State, board: p.board, libs: libs, liberties: len(libs)}
+	c.link(p)
 	return &c, nil
 }
 
-func (c *chain) encode() string {
-	var sb strings.Builder
-	if c.isBlack {
-		sb.WriteString("1")
+// link appends the stone to the list of the chain and joins it to the union-find tree, without updating
+// the liberties.
+func (c *chain) link(p *Point) {
+	if c.root == nil {
+		c.root, c.first = p, p
+		p.parent, p.owner = p, c
 	} else {
-		sb.WriteString("0")
+		p.parent = c.root
+		c.last.next = p
+	}
+	p.next = nil
+	c.last = p
+	c.count++
+}
+
+// stones returns the stones of the chain, in the order they joined it.
+func (c *chain) stones() []*Point {
+	stones := make([]*Point, 0, c.count)
+	for p := c.first; p != nil; p = p.next {
+		stones = append(stones, p)
 	}
+	return stones
+}
+
+func (c *chain) encode() string {
+	var sb strings.Builder
+	sb.WriteString(strconv.Itoa(int(c.state)))
 	sb.WriteString("-")
 	sb.WriteString(strconv.Itoa(c.id))
 	sb.WriteString("-")
 	sb.WriteString(strconv.Itoa(c.liberties))
 	sb.WriteString("-")
-	for i, p := range c.points {
+	for p := c.first; p != nil; p = p.next {
 		sb.WriteString(strconv.Itoa(p.X))
 		sb.WriteString(",")
 		sb.WriteString(strconv.Itoa(p.Y))
-		if i < (len(c.points) - 1) {
+		if p.next != nil {
 			sb.WriteString("|")
 		}
 	}
 	return sb.String()
 }
 
+func (c *chain) addLiberty(p *Point) {
+	c.libs[p] = true
+	c.liberties = len(c.libs)
+}
+
+func (c *chain) removeLiberty(p *Point) {
+	delete(c.libs, p)
+	c.liberties = len(c.libs)
+}
+
 func (c *chain) add(p *Point) {
-	c.points = append(c.points, p)
-	c.updateLiberties()
+	c.link(p)
+	for _, n := range p.neighbords {
+		if n.State == FREE {
+			c.libs[n] = true
+		}
+	}
+	c.liberties = len(c.libs)
 }
 
+// updateLiberties recomputes the liberties from scratch.
 func (c *chain) updateLiberties() {
-	l := 0
-	seenFree := make(map[*Point]bool)
-	for _, p := range c.points {
+	c.libs = make(map[*Point]bool)
+	for p := c.first; p != nil; p = p.next {
 		for _, n := range p.neighbords {
-			if _, seen := seenFree[n]; !seen && n.State == FREE {
-				l += 1
-				seenFree[n] = true
+			if n.State == FREE {
+				c.libs[n] = true
 			}
 		}
 	}
-	c.liberties = l
+	c.liberties = len(c.libs)
 }
 
+// merge joins c2 into c1, which keeps its id. It takes constant time, apart from adding the liberties of the
+// chain with fewer ones to the other.
 func (c1 *chain) merge(c2 *chain) {
-	for _, p2 := range c2.points {
-		p2.chainId = c1.id
+	root, child := c1.root, c2.root
+	if c2.count > c1.count {
+		root, child = child, root
+	}
+	child.parent, child.owner = root, nil
+	root.owner = c1
+	c1.root = root
+	c1.last.next = c2.first
+	c1.last = c2.last
+	c1.count += c2.count
+	if len(c2.libs) > len(c1.libs) {
+		c1.libs, c2.libs = c2.libs, c1.libs
 	}
-	c1.points = append(c1.points, c2.points...)
-	c1.updateLiberties()
+	for l := range c2.libs {
+		c1.libs[l] = true
+	}
+	c1.liberties = len(c1.libs)
 	delete(c1.board.chains, c2.id)
 }
 
 func (c *chain) free() {
-	for _, p := range c.points {
-		p.updateNeighborsLiberties(p.State == BLACK)
-		p.State = FREE
+	stones := c.stones()
+	for _, p := range stones {
+		p.free()
+	}
+	for _, p := range stones {
+		p.updateNeighborsLiberties()
 	}
 	c.board = nil
 }
@@ -0,0 +1,110 @@
+package game
+
+import (
+	"math/rand/v2"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// playRandomGame plays random moves, some of them illegal, validating the board against the flood fill
+// reference and checking that chain ids are never reused after every move.
+func playRandomGame(seed uint64, size, moves int) bool {
+	r := rand.New(rand.NewPCG(seed, seed))
+	g, _ := NewGame(size)
+	alive := make(map[int]bool)
+	dead := make(map[int]bool)
+	black := true
+	for i := 0; i < moves; i++ {
+		var err error
+		if r.IntN(5) == 0 { //any point, maybe an illegal move
+			err = g.Play(r.IntN(size), r.IntN(size), black)
+		} else if legal := g.LegalMoves(black); len(legal) > 0 {
+			m := legal[r.IntN(len(legal))]
+			err = g.Play(m.X, m.Y, black)
+		} else {
+			err = g.Pass(black)
+		}
+		if err == nil {
+			black = !black
+		}
+		for id := range alive {
+			if _, ok := g.board.chains[id]; !ok {
+				dead[id] = true
+			}
+		}
+		alive = make(map[int]bool)
+		for id := range g.board.chains {
+			if dead[id] {
+				return false
+			}
+			alive[id] = true
+		}
+		if g.Validate() != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func TestChainsMatchFloodFill(t *testing.T) {
+	for _, size := range []int{2, 3, 5, 9} {
+		check := func(seed uint64) bool {
+			return playRandomGame(seed, size, 4*size*size)
+		}
+		if err := quick.Check(check, &quick.Config{MaxCount: 50}); err != nil {
+			t.Errorf("size %v: %v", size, err)
+		}
+	}
+}
+
+func TestChainIdsNeverReused(t *testing.T) {
+	//      * B * * *
+	//      B W B * *
+	//      * B * * *
+	assert := assert.New(t)
+	g, _ := NewGame(5)
+	g.Play(0, 1, true)
+	g.Play(1, 1, false)
+	g.Play(1, 0, true)
+	g.Pass(false)
+	g.Play(1, 2, true)
+	g.Pass(false)
+	g.Play(2, 1, true) //captures white chain 2
+	assert.Equal(1, g.WhiteCaptures)
+	assert.NotContains(g.board.chains, 2)
+	assert.Equal(0, g.board.field[1][1].chainId())
+	g.Play(1, 1, false) //self-capture
+	assert.NotContains(g.board.chains, 2)
+	g.Play(4, 4, false)
+	assert.Equal(7, g.board.field[4][4].chainId())
+	assert.NoError(g.Validate())
+}
+
+func TestMergeLinksRoots(t *testing.T) {
+	//      B B * * *
+	//      B * * * *
+	//      B B B B *
+	assert := assert.New(t)
+	g, _ := NewGame(5)
+	f := g.board.field
+	for _, m := range [][2]int{{0, 0}, {0, 1}, {2, 0}, {2, 1}, {2, 2}, {2, 3}} {
+		g.Play(m[0], m[1], true)
+		g.Pass(false)
+	}
+	assert.Equal(1, f[0][1].chainId())
+	assert.Equal(2, f[2][3].chainId())
+	g.Play(1, 0, true)
+	assert.NoError(g.Validate())
+	assert.NotContains(g.board.chains, 2)
+	c := g.board.chains[1]
+	assert.Equal(7, c.count)
+	//the stones of the absorbed chain keep their parent, only the root of the smaller chain is linked
+	assert.Same(&f[2][0], c.root)
+	assert.Same(&f[2][0], f[2][3].parent)
+	assert.Same(&f[2][0], f[0][0].parent)
+	assert.Same(&f[0][0], f[0][1].parent)
+	assert.Equal(1, f[0][1].chainId())
+	assert.Equal([]*Point{&f[0][0], &f[0][1], &f[1][0], &f[2][0], &f[2][1], &f[2][2], &f[2][3]}, c.stones())
+}
@@ -0,0 +1,183 @@
+package game
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	influenceRadius = 4
+	maxEnclosedArea = 1.0 / 3 //max fraction of the board an enclosed region can have to count as territory
+)
+
+// EstimateOptions configures GoGame.Estimate.
+type EstimateOptions struct {
+	Komi     float64 // Points added to the white score.
+	Playouts int     // Random playouts to run from the position. If 0, only the static evaluation is used.
+}
+
+// Estimate is the evaluation of an unfinished game.
+type Estimate struct {
+	Ownership [][]float64 // Ownership of each point, from -1 (surely white) to 1 (surely black).
+	Score     float64     // Estimated black score minus white score by area counting, komi included.
+}
+
+// Estimate evaluates who owns each point of the board and the resulting score.
+//
+// Without playouts, free points take the owner of the region they belong to if it is enclosed by a single
+// player, or the influence of the stones around otherwise, and chains in atari inside the influence of the
+// opponent are counted as dead. With playouts, the ownership is the average of the final position of
+// random games. In both cases, chains and regions found by PassAlive are always sure.
+func (g *GoGame) Estimate(opts EstimateOptions) Estimate {
+	size := g.board.size
+	own := make([][]float64, size)
+	for x := range own {
+		own[x] = make([]float64, size)
+	}
+	if opts.Playouts > 0 {
+		g.playoutOwnership(own, opts.Playouts)
+	} else {
+		g.staticOwnership(own)
+	}
+	for _, black := range []bool{true, false} {
+		sure := -1.0
+		if black {
+			sure = 1.0
+		}
+		chains, regions := g.PassAlive(black)
+		for _, points := range append(chains, regions...) {
+			for _, c := range points {
+				own[c.X][c.Y] = sure
+			}
+		}
+	}
+	score := -opts.Komi
+	for x := range own {
+		for y := range own[x] {
+			score += own[x][y]
+		}
+	}
+	return Estimate{Ownership: own, Score: score}
+}
+
+func (g *GoGame) staticOwnership(own [][]float64) {
+	b := g.board
+	influence := g.influence()
+	//free regions enclosed by a single player, not too big, are its territory
+	seen := make(map[*Point]bool)
+	for x := range b.field {
+		for y := range b.field[x] {
+			start := &b.field[x][y]
+			if start.State != FREE || seen[start] {
+				continue
+			}
+			region := []*Point{start}
+			seen[start] = true
+			border := make(map[pointStateType]bool)
+			for i := 0; i < len(region); i++ {
+				for _, n := range region[i].neighbords {
+					if n.State != FREE {
+						border[n.State] = true
+					} else if !seen[n] {
+						seen[n] = true
+						region = append(region, n)
+					}
+				}
+			}
+			enclosed := len(border) == 1 && float64(len(region)) <= maxEnclosedArea*float64(b.size*b.size)
+			for _, p := range region {
+				switch {
+				case enclosed && border[BLACK]:
+					own[p.X][p.Y] = 1
+				case enclosed && border[WHITE]:
+					own[p.X][p.Y] = -1
+				default:
+					own[p.X][p.Y] = math.Tanh(influence[p.X][p.Y])
+				}
+			}
+		}
+	}
+	//stones are owned by their player, unless their chain is in atari inside the opponent influence
+	for _, c := range b.chains {
+		sign := -1.0
+		if c.state == BLACK {
+			sign = 1.0
+		}
+		total := 0.0
+		for _, p := range c.stones() {
+			total += influence[p.X][p.Y]
+		}
+		dead := c.liberties == 1 && total*sign < 0
+		for _, p := range c.stones() {
+			if dead {
+				own[p.X][p.Y] = -sign
+			} else {
+				own[p.X][p.Y] = sign
+			}
+		}
+	}
+}
+
+// influence returns for each point the sum of the influence of all the stones, positive for black,
+// halving with each step of distance up to influenceRadius.
+func (g *GoGame) influence() [][]float64 {
+	b := g.board
+	influence := make([][]float64, b.size)
+	for x := range influence {
+		influence[x] = make([]float64, b.size)
+	}
+	for x := range b.field {
+		for y := range b.field[x] {
+			sign := 0.0
+			switch b.field[x][y].State {
+			case BLACK:
+				sign = 1
+			case WHITE:
+				sign = -1
+			default:
+				continue
+			}
+			for i := max(0, x-influenceRadius); i <= min(b.size-1, x+influenceRadius); i++ {
+				for j := max(0, y-influenceRadius); j <= min(b.size-1, y+influenceRadius); j++ {
+					d := abs(i-x) + abs(j-y)
+					if d <= influenceRadius {
+						influence[i][j] += sign / float64(int(1)<<d)
+					}
+				}
+			}
+		}
+	}
+	return influence
+}
+
+func (g *GoGame) playoutOwnership(own [][]float64, playouts int) {
+	root := g.FastBoard()
+	b := root.Clone()
+	r := rand.New(rand.NewPCG(uint64(playouts), uint64(time.Now().UnixNano())))
+	size := g.board.size
+	for k := 0; k < playouts; k++ {
+		b.CopyFrom(root)
+		b.playout(colorOf(!g.BlackPlayedLast), 0, r)
+		for i := range b.stones {
+			switch b.owner(i) {
+			case BLACK:
+				own[i/size][i%size] += 1
+			case WHITE:
+				own[i/size][i%size] -= 1
+			}
+		}
+	}
+	for x := range own {
+		for y := range own[x] {
+			own[x][y] /= float64(playouts)
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,59 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateStatic(t *testing.T) {
+	assert := assert.New(t)
+	g, _ := NewGame(5)
+	e := g.Estimate(EstimateOptions{})
+	assert.Len(e.Ownership, 5)
+	assert.Equal(0.0, e.Score)
+	g = newGameFromRows(t,
+		"*B*W*",
+		"*B*W*",
+		"BB*WW",
+		"*****",
+		"***W*",
+	)
+	e = g.Estimate(EstimateOptions{Komi: 0.5})
+	assert.Equal(1.0, e.Ownership[0][0]) //enclosed by black
+	assert.Equal(1.0, e.Ownership[2][1])
+	assert.Equal(-1.0, e.Ownership[1][4]) //enclosed by white
+	assert.Less(e.Ownership[4][4], 0.0)   //white influence
+	assert.Less(e.Score, 0.0)
+}
+
+func TestEstimateDeadStones(t *testing.T) {
+	assert := assert.New(t)
+	g := newGameFromRows(t,
+		"*W***",
+		"WBW**",
+		"*W***",
+		"*****",
+		"*****",
+	)
+	e := g.Estimate(EstimateOptions{})
+	assert.Equal(-1.0, e.Ownership[1][1]) //black stone in atari, dead
+}
+
+func TestEstimatePlayouts(t *testing.T) {
+	assert := assert.New(t)
+	g := newGameFromRows(t,
+		"*B*B*",
+		"BBBBB",
+		"*****",
+		"WWWWW",
+		"*W*W*",
+	)
+	e := g.Estimate(EstimateOptions{Playouts: 50, Komi: 0.5})
+	assert.Equal(1.0, e.Ownership[0][0]) //pass-alive
+	assert.Equal(-1.0, e.Ownership[4][4])
+	for y := 0; y < 5; y++ {
+		assert.InDelta(0.0, e.Ownership[2][y], 1.0)
+	}
+	assert.InDelta(-0.5, e.Score, 5.0)
+}
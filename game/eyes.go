@@ -0,0 +1,219 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+)
+
+const maxEyeSize = 7 //regions bigger than this are open areas, not eyes
+
+type EyeKind int
+
+const (
+	NotEye   EyeKind = iota // Region too big to be an eye.
+	RealEye                 // Eye the opponent cannot destroy.
+	FalseEye                // Single point eye whose surrounding stones can be put in atari through its diagonals.
+)
+
+// EyeRegion is a region next to a group, made of free points and maybe dead opponent stones.
+type EyeRegion struct {
+	Points []Coord
+	Kind   EyeKind
+}
+
+// Seki is a set of chains of both players living together, where neither player can capture the other.
+type Seki struct {
+	Chains [][]Coord // Stones of the chains in seki.
+	Dame   []Coord   // Liberties shared by both players, which are territory of nobody.
+}
+
+// Eyes classifies the regions next to the group in (x, y) as eyes, false eyes or open areas.
+// A region is the connected set of points without stones of the group player that contains some liberty of it.
+func (g *GoGame) Eyes(x, y int) ([]EyeRegion, error) {
+	if x < 0 || x > (g.board.size-1) || y < 0 || y > (g.board.size-1) {
+		return nil, fmt.Errorf("invalid position (%v, %v)", x, y)
+	}
+	p := &g.board.field[x][y]
+	if p.State == FREE {
+		return nil, fmt.Errorf("no group in (%v, %v)", x, y)
+	}
+	return g.board.eyes(g.board.chains[p.chainId()]), nil
+}
+
+func (b *board) eyes(c *chain) []EyeRegion {
+	state := c.state
+	seen := make(map[*Point]bool)
+	eyes := make([]EyeRegion, 0)
+	for _, l := range sortedLiberties(c) {
+		if seen[l] {
+			continue
+		}
+		region := []*Point{l}
+		seen[l] = true
+		for i := 0; i < len(region); i++ {
+			for _, n := range region[i].neighbords {
+				if n.State != state && !seen[n] {
+					seen[n] = true
+					region = append(region, n)
+				}
+			}
+		}
+		eye := EyeRegion{Points: coordsOf(region), Kind: RealEye}
+		if len(region) > maxEyeSize {
+			eye.Kind = NotEye
+		} else if len(region) == 1 && b.isFalseEye(region[0], state) {
+			eye.Kind = FalseEye
+		}
+		eyes = append(eyes, eye)
+	}
+	return eyes
+}
+
+// isFalseEye checks the diagonals of a single point eye of the player, in the topology of the board: the
+// opponent can break the eye with one diagonal on the edge of the board, or two in the center.
+func (b *board) isFalseEye(p *Point, state pointStateType) bool {
+	diags := b.geo.diags[p.X*b.size+p.Y]
+	enemies := 0
+	for _, d := range diags {
+		if s := b.field[d/b.size][d%b.size].State; s != FREE && s != state {
+			enemies += 1
+		}
+	}
+	if len(diags) < 4 {
+		return enemies > 0
+	}
+	return enemies > 1
+}
+
+// Seki finds the chains in seki: chains of both players that share liberties, where every other liberty
+// is a real eye and playing in any shared liberty puts the player in atari.
+func (g *GoGame) Seki() []Seki {
+	return g.board.seki()
+}
+
+func (b *board) seki() []Seki {
+	fb := (&GoGame{board: b}).FastBoard()
+	group := make(map[int]int) //chain id to the seki it belongs to, as the id of one of its chains
+	find := func(id int) int {
+		for group[id] != id {
+			id = group[id]
+		}
+		return id
+	}
+	dame := make(map[*Point]bool)
+	for _, a := range b.sortedChains() {
+		if a.state != BLACK || a.liberties < 2 {
+			continue
+		}
+		for _, o := range b.sortedChains() {
+			if o.state != WHITE || o.liberties < 2 {
+				continue
+			}
+			shared := make([]*Point, 0)
+			for l := range a.libs {
+				if o.libs[l] {
+					shared = append(shared, l)
+				}
+			}
+			if len(shared) == 0 || !b.onlyEyesBut(a, o) || !b.onlyEyesBut(o, a) {
+				continue
+			}
+			approachable := false
+			for _, s := range shared {
+				for _, c := range []pointStateType{BLACK, WHITE} {
+					i := s.X*b.size + s.Y
+					if !fb.isLegal(i, c) {
+						continue
+					}
+					after := fb.Clone()
+					if after.play(i, c) > 0 || after.liberties(i) > 1 {
+						approachable = true
+					}
+				}
+			}
+			if approachable {
+				continue
+			}
+			for _, id := range []int{a.id, o.id} {
+				if _, ok := group[id]; !ok {
+					group[id] = id
+				}
+			}
+			group[find(o.id)] = find(a.id)
+			for _, s := range shared {
+				dame[s] = true
+			}
+		}
+	}
+	sekis := make(map[int]*Seki)
+	roots := make([]int, 0)
+	for _, c := range b.sortedChains() {
+		if _, ok := group[c.id]; !ok {
+			continue
+		}
+		r := find(c.id)
+		if sekis[r] == nil {
+			sekis[r] = &Seki{Chains: make([][]Coord, 0), Dame: make([]Coord, 0)}
+			roots = append(roots, r)
+		}
+		sekis[r].Chains = append(sekis[r].Chains, coordsOf(c.stones()))
+		for _, l := range sortedLiberties(c) {
+			if dame[l] && !slices.Contains(sekis[r].Dame, Coord{l.X, l.Y}) {
+				sekis[r].Dame = append(sekis[r].Dame, Coord{l.X, l.Y})
+			}
+		}
+	}
+	result := make([]Seki, 0, len(roots))
+	for _, r := range roots {
+		result = append(result, *sekis[r])
+	}
+	return result
+}
+
+// onlyEyesBut reports if every liberty of chain c not shared with chain o is inside a real eye of c.
+func (b *board) onlyEyesBut(c, o *chain) bool {
+	inEye := make(map[Coord]bool)
+	for _, e := range b.eyes(c) {
+		if e.Kind == RealEye {
+			for _, p := range e.Points {
+				inEye[p] = true
+			}
+		}
+	}
+	for l := range c.libs {
+		if !o.libs[l] && !inEye[Coord{l.X, l.Y}] {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedChains returns the chains in the order of their first stone in the board.
+func (b *board) sortedChains() []*chain {
+	chains := make([]*chain, 0, len(b.chains))
+	seen := make(map[int]bool)
+	for x := range b.field {
+		for y := range b.field[x] {
+			p := &b.field[x][y]
+			if p.State != FREE && !seen[p.chainId()] {
+				seen[p.chainId()] = true
+				chains = append(chains, b.chains[p.chainId()])
+			}
+		}
+	}
+	return chains
+}
+
+func sortedLiberties(c *chain) []*Point {
+	libs := make([]*Point, 0, len(c.libs))
+	for l := range c.libs {
+		libs = append(libs, l)
+	}
+	slices.SortFunc(libs, func(a, b *Point) int {
+		if a.X != b.X {
+			return a.X - b.X
+		}
+		return a.Y - b.Y
+	})
+	return libs
+}
@@ -0,0 +1,63 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEyes(t *testing.T) {
+	assert := assert.New(t)
+	g := newGameFromRows(t,
+		"*B*B*",
+		"BBBBB",
+		"*****",
+		"**W**",
+		"*****",
+	)
+	eyes, err := g.Eyes(1, 1)
+	assert.NoError(err)
+	assert.Len(eyes, 4)
+	assert.Equal(EyeRegion{Points: []Coord{{0, 0}}, Kind: RealEye}, eyes[0])
+	assert.Equal(EyeRegion{Points: []Coord{{0, 2}}, Kind: RealEye}, eyes[1])
+	assert.Equal(EyeRegion{Points: []Coord{{0, 4}}, Kind: RealEye}, eyes[2])
+	assert.Equal(NotEye, eyes[3].Kind)
+	assert.Len(eyes[3].Points, 15)
+	_, err = g.Eyes(2, 2)
+	assert.Error(err)
+
+	g = newGameFromRows(t,
+		"B*BW*",
+		"*BW**",
+		"*****",
+		"*****",
+		"*****",
+	)
+	eyes, err = g.Eyes(0, 2)
+	assert.NoError(err)
+	assert.Equal([]EyeRegion{{Points: []Coord{{0, 1}}, Kind: FalseEye}}, eyes)
+}
+
+func TestSeki(t *testing.T) {
+	assert := assert.New(t)
+	g := newGameFromRows(t,
+		"*B*W*",
+		"BBBWW",
+		"WWWBB",
+		"*****",
+		"*****",
+	)
+	assert.Equal([]Seki{{
+		Chains: [][]Coord{{{0, 1}, {1, 0}, {1, 1}, {1, 2}}, {{0, 3}, {1, 3}, {1, 4}}},
+		Dame:   []Coord{{0, 2}},
+	}}, g.Seki())
+	//black has an outside liberty, so it can approach
+	g = newGameFromRows(t,
+		"*B*W*",
+		"BBBWW",
+		"*WWBB",
+		"*****",
+		"*****",
+	)
+	assert.Empty(g.Seki())
+}
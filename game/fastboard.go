@@ -0,0 +1,405 @@
+package game
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// fastGeometry holds the neighbours of every point of a board, using flat indices (x*size + y).
+// It never changes after creation, so it is shared between all the fast boards of the same size and topology.
+type fastGeometry struct {
+	size  int
+	nbrs  [][]int
+	diags [][]int
+}
+
+type geometryKey struct {
+	size     int
+	topology Topology //only the value types of this package, which are comparable
+}
+
+var (
+	geometriesMu sync.Mutex
+	geometries   = make(map[geometryKey]*fastGeometry)
+)
+
+// geometryFor returns the geometry of the topology. The diagonals of a point are the points two steps away
+// sharing at least two neighbours with it, like in the square grid. Only the geometries of Grid, Torus and
+// Cylinder are cached: other topologies, like custom graphs, may not be comparable or may change, so games
+// compute their geometry once when they are created and keep it in their board.
+func geometryFor(size int, t Topology) *fastGeometry {
+	cached := false
+	switch t.(type) {
+	case Grid, Torus, Cylinder:
+		cached = true
+	}
+	key := geometryKey{size, t}
+	if cached {
+		geometriesMu.Lock()
+		defer geometriesMu.Unlock()
+		if geo, ok := geometries[key]; ok {
+			return geo
+		}
+	}
+	n := size * size
+	geo := &fastGeometry{size: size, nbrs: make([][]int, n), diags: make([][]int, n)}
+	for i := 0; i < n; i++ {
+		for _, c := range neighborsOf(t, Coord{i / size, i % size}, size) {
+			geo.nbrs[i] = append(geo.nbrs[i], c.X*size+c.Y)
+		}
+	}
+	for i := 0; i < n; i++ {
+		shared := make(map[int]int)
+		for _, a := range geo.nbrs[i] {
+			for _, d := range geo.nbrs[a] {
+				if d != i && !slices.Contains(geo.nbrs[i], d) {
+					shared[d] += 1
+				}
+			}
+		}
+		for _, d := range slices.Sorted(maps.Keys(shared)) {
+			if shared[d] >= 2 {
+				geo.diags[i] = append(geo.diags[i], d)
+			}
+		}
+	}
+	if cached {
+		geometries[key] = geo
+	}
+	return geo
+}
+
+// FastBoard is a compact array-based board, cheap to copy, for search algorithms and "what-if" analysis.
+// Points are stored with flat indices (x*size + y) and the neighbours of each point are precomputed.
+// Chains are kept as circular linked lists of stones (next) with a common head.
+//
+// Unlike GoGame, a FastBoard does not check turn order, and it forbids immediate ko recaptures.
+type FastBoard struct {
+	geo     *fastGeometry
+	stones  []pointStateType
+	head    []int //head stone of the chain of each stone
+	next    []int //next stone in the chain of each stone
+	count   []int //stones of each chain, only valid in the head
+	empty   []int //all the free points
+	emptyAt []int //position of each free point in empty. -1 if taken
+	ko      int   //point forbidden by the ko rule. -1 if none
+	mark    []int //scratch marks for liberty counting
+	stamp   int
+}
+
+func NewFastBoard(size int) *FastBoard {
+	return newFastBoard(size, Grid{})
+}
+
+func newFastBoard(size int, t Topology) *FastBoard {
+	return newFastBoardOf(geometryFor(size, t))
+}
+
+// newFastBoardOf creates an empty fast board with the given geometry.
+func newFastBoardOf(geo *fastGeometry) *FastBoard {
+	n := geo.size * geo.size
+	b := &FastBoard{
+		geo:     geo,
+		stones:  make([]pointStateType, n),
+		head:    make([]int, n),
+		next:    make([]int, n),
+		count:   make([]int, n),
+		empty:   make([]int, n),
+		emptyAt: make([]int, n),
+		ko:      -1,
+		mark:    make([]int, n),
+	}
+	for i := 0; i < n; i++ {
+		b.empty[i] = i
+		b.emptyAt[i] = i
+	}
+	return b
+}
+
+// FastBoard copies the stones of the game in a new fast board.
+func (g *GoGame) FastBoard() *FastBoard {
+	size := g.board.size
+	b := newFastBoardOf(g.board.geo)
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			if s := g.board.field[x][y].State; s != FREE {
+				b.place(x*size+y, s)
+			}
+		}
+	}
+	return b
+}
+
+// CopyFrom overwrites the board with the position of src, reusing its memory when possible.
+func (b *FastBoard) CopyFrom(src *FastBoard) {
+	b.geo = src.geo
+	b.stones = append(b.stones[:0], src.stones...)
+	b.head = append(b.head[:0], src.head...)
+	b.next = append(b.next[:0], src.next...)
+	b.count = append(b.count[:0], src.count...)
+	b.empty = append(b.empty[:0], src.empty...)
+	b.emptyAt = append(b.emptyAt[:0], src.emptyAt...)
+	b.ko = src.ko
+	if len(b.mark) != len(src.mark) {
+		b.mark = make([]int, len(src.mark))
+	}
+}
+
+func (b *FastBoard) Clone() *FastBoard {
+	c := &FastBoard{}
+	c.CopyFrom(b)
+	return c
+}
+
+func (b *FastBoard) Size() int {
+	return b.geo.size
+}
+
+// At returns the state of the point in (x, y).
+func (b *FastBoard) At(x, y int) pointStateType {
+	return b.stones[x*b.geo.size+y]
+}
+
+func (b *FastBoard) inside(x, y int) bool {
+	return x >= 0 && x < b.geo.size && y >= 0 && y < b.geo.size
+}
+
+// IsLegal reports if the player can put a stone in (x, y).
+func (b *FastBoard) IsLegal(x, y int, black bool) bool {
+	return b.inside(x, y) && b.isLegal(x*b.geo.size+y, colorOf(black))
+}
+
+// Play puts a stone of the player in (x, y) and returns the number of captured stones.
+func (b *FastBoard) Play(x, y int, black bool) (int, error) {
+	if !b.inside(x, y) {
+		return 0, fmt.Errorf("invalid position (%v, %v)", x, y)
+	}
+	i := x*b.geo.size + y
+	c := colorOf(black)
+	if !b.isLegal(i, c) {
+		return 0, fmt.Errorf("illegal move in (%v, %v)", x, y)
+	}
+	return b.play(i, c), nil
+}
+
+// Pass lets the player in turn pass, clearing the ko point.
+func (b *FastBoard) Pass() {
+	b.pass()
+}
+
+// Liberties returns the number of liberties of the chain in (x, y). 0 if the point is free.
+func (b *FastBoard) Liberties(x, y int) int {
+	if !b.inside(x, y) || b.At(x, y) == FREE {
+		return 0
+	}
+	return b.liberties(x*b.geo.size + y)
+}
+
+// String uses the same representation as GoGame.
+func (b *FastBoard) String() string {
+	var sb strings.Builder
+	for _, s := range b.stones {
+		sb.WriteString(Point{State: s}.String())
+	}
+	return sb.String()
+}
+
+func colorOf(black bool) pointStateType {
+	if black {
+		return BLACK
+	}
+	return WHITE
+}
+
+func opponent(c pointStateType) pointStateType {
+	if c == BLACK {
+		return WHITE
+	}
+	return BLACK
+}
+
+// hasLibertyBut reports whether the chain of stone i has a liberty other than point p.
+func (b *FastBoard) hasLibertyBut(i, p int) bool {
+	s := i
+	for {
+		for _, n := range b.geo.nbrs[s] {
+			if b.stones[n] == FREE && n != p {
+				return true
+			}
+		}
+		s = b.next[s]
+		if s == i {
+			return false
+		}
+	}
+}
+
+// liberties counts the liberties of the chain of stone i.
+func (b *FastBoard) liberties(i int) int {
+	b.stamp++
+	l := 0
+	s := i
+	for {
+		for _, n := range b.geo.nbrs[s] {
+			if b.stones[n] == FREE && b.mark[n] != b.stamp {
+				b.mark[n] = b.stamp
+				l++
+			}
+		}
+		s = b.next[s]
+		if s == i {
+			return l
+		}
+	}
+}
+
+// isLegal reports if color c can play in point i, without suicide or immediate ko recapture.
+func (b *FastBoard) isLegal(i int, c pointStateType) bool {
+	if b.stones[i] != FREE || i == b.ko {
+		return false
+	}
+	for _, n := range b.geo.nbrs[i] {
+		switch b.stones[n] {
+		case FREE:
+			return true
+		case c:
+			if b.hasLibertyBut(n, i) {
+				return true
+			}
+		default:
+			if !b.hasLibertyBut(n, i) { //captures
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isEye reports if point i is an eye-like point of color c, which playouts must not fill.
+func (b *FastBoard) isEye(i int, c pointStateType) bool {
+	if b.stones[i] != FREE {
+		return false
+	}
+	for _, n := range b.geo.nbrs[i] {
+		if b.stones[n] != c {
+			return false
+		}
+	}
+	enemies := 0
+	for _, d := range b.geo.diags[i] {
+		if b.stones[d] != FREE && b.stones[d] != c {
+			enemies++
+		}
+	}
+	if len(b.geo.diags[i]) < 4 {
+		return enemies == 0
+	}
+	return enemies < 2
+}
+
+// play puts a stone of color c in point i, that must be legal, and returns the captured stones, of any other color.
+func (b *FastBoard) play(i int, c pointStateType) int {
+	b.place(i, c)
+	captured := 0
+	lastCaptured := -1
+	for _, n := range b.geo.nbrs[i] {
+		if b.stones[n] != FREE && b.stones[n] != c && !b.hasLibertyBut(n, -1) {
+			lastCaptured = n
+			captured += b.remove(n)
+		}
+	}
+	b.ko = -1
+	if captured == 1 && b.count[b.head[i]] == 1 && b.liberties(i) == 1 {
+		b.ko = lastCaptured
+	}
+	return captured
+}
+
+func (b *FastBoard) pass() {
+	b.ko = -1
+}
+
+// place puts a stone without checking captures, merging it with its neighbour chains.
+func (b *FastBoard) place(i int, c pointStateType) {
+	b.stones[i] = c
+	b.head[i] = i
+	b.next[i] = i
+	b.count[i] = 1
+	last := b.empty[len(b.empty)-1]
+	b.empty[b.emptyAt[i]] = last
+	b.emptyAt[last] = b.emptyAt[i]
+	b.empty = b.empty[:len(b.empty)-1]
+	b.emptyAt[i] = -1
+	for _, n := range b.geo.nbrs[i] {
+		if b.stones[n] == c && b.head[n] != b.head[i] {
+			b.merge(b.head[i], b.head[n])
+		}
+	}
+}
+
+// merge joins the chains with heads h1 and h2, relabeling the smaller one.
+func (b *FastBoard) merge(h1, h2 int) {
+	if b.count[h1] < b.count[h2] {
+		h1, h2 = h2, h1
+	}
+	s := h2
+	for {
+		b.head[s] = h1
+		s = b.next[s]
+		if s == h2 {
+			break
+		}
+	}
+	b.next[h1], b.next[h2] = b.next[h2], b.next[h1]
+	b.count[h1] += b.count[h2]
+}
+
+// remove takes out the chain of stone i from the board and returns its size.
+func (b *FastBoard) remove(i int) int {
+	n := b.count[b.head[i]]
+	s := i
+	for {
+		b.stones[s] = FREE
+		b.emptyAt[s] = len(b.empty)
+		b.empty = append(b.empty, s)
+		s = b.next[s]
+		if s == i {
+			break
+		}
+	}
+	return n
+}
+
+// owner returns the player owning point i at the end of the game: the color of its stone,
+// or the color of all its neighbours if it is free. FREE if it is neutral.
+func (b *FastBoard) owner(i int) pointStateType {
+	if b.stones[i] != FREE {
+		return b.stones[i]
+	}
+	owner := FREE
+	for _, n := range b.geo.nbrs[i] {
+		if b.stones[n] == FREE || (owner != FREE && b.stones[n] != owner) {
+			return FREE
+		}
+		owner = b.stones[n]
+	}
+	return owner
+}
+
+// areaScore returns black score minus white score by area counting,
+// assuming all the stones are alive. komi is added to white.
+func (b *FastBoard) areaScore(komi float64) float64 {
+	score := -komi
+	for i := range b.stones {
+		switch b.owner(i) {
+		case BLACK:
+			score++
+		case WHITE:
+			score--
+		}
+	}
+	return score
+}
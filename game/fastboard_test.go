@@ -0,0 +1,130 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFastBoardCaptureAndKo(t *testing.T) {
+	//      * B W * *
+	//      B W * W *
+	//      * B W * *
+	assert := assert.New(t)
+	b := NewFastBoard(5)
+	b.play(1, BLACK)
+	b.play(2, WHITE)
+	b.play(5, BLACK)
+	b.play(6, WHITE)
+	b.play(11, BLACK)
+	b.play(12, WHITE)
+	b.play(24, BLACK)
+	b.play(8, WHITE)
+	assert.Equal(BLACK, b.stones[1])
+	assert.True(b.isLegal(7, BLACK))
+	assert.Equal(1, b.play(7, BLACK)) //captures white in (1, 1)
+	assert.Equal(FREE, b.stones[6])
+	assert.Equal(6, b.ko)
+	assert.False(b.isLegal(6, WHITE)) //ko
+	b.pass()
+	assert.True(b.isLegal(6, WHITE))
+	assert.False(b.isLegal(0, WHITE)) //self-capture
+	assert.Equal(3, b.liberties(5))
+	assert.Equal(len(b.stones)-8, len(b.empty))
+}
+
+func TestFastBoardFromGame(t *testing.T) {
+	assert := assert.New(t)
+	g, _ := NewGame(5)
+	g.Play(1, 1, true)
+	g.Play(1, 2, false)
+	g.Play(2, 1, true)
+	b := g.FastBoard()
+	assert.Equal(BLACK, b.stones[6])
+	assert.Equal(WHITE, b.stones[7])
+	assert.Equal(b.head[6], b.head[11])
+	assert.Equal(2, b.count[b.head[6]])
+	assert.Equal(5, b.liberties(6))
+	c := b.Clone()
+	c.play(0, WHITE)
+	assert.Equal(FREE, b.stones[0])
+	assert.Equal(WHITE, c.stones[0])
+}
+
+func TestFastBoardPlay(t *testing.T) {
+	assert := assert.New(t)
+	b := NewFastBoard(5)
+	var err error
+	_, err = b.Play(-1, 0, true)
+	assert.Error(err)
+	_, err = b.Play(0, 1, true)
+	assert.NoError(err)
+	_, err = b.Play(0, 1, false)
+	assert.Error(err)
+	_, err = b.Play(1, 0, true)
+	assert.NoError(err)
+	assert.False(b.IsLegal(0, 0, false))
+	assert.True(b.IsLegal(0, 0, true))
+	assert.Equal(3, b.Liberties(0, 1))
+	assert.Equal(0, b.Liberties(2, 2))
+	assert.Equal(BLACK, b.At(1, 0))
+	assert.Equal(5, b.Size())
+	assert.Equal("*B***B*******************", b.String())
+}
+
+func TestFastBoardMultiColor(t *testing.T) {
+	assert := assert.New(t)
+	g, _ := NewMultiColorGame(5, 3)
+	g.PlayColor(0, 1, 0)
+	g.PlayColor(4, 4, 1)
+	g.PlayColor(0, 0, 2)
+	g.PlayColor(2, 2, 0)
+	g.PlayColor(3, 3, 1)
+	g.PlayColor(1, 1, 2)
+	b := g.FastBoard()
+	assert.Equal(RED, b.stones[0])
+	assert.Equal(1, b.play(5, BLACK)) //captures red in (0, 0)
+	assert.Equal(FREE, b.stones[0])
+	assert.False(b.isEye(0, BLACK)) //red diagonal in the corner
+	_, err := GenMove(g, true, MCTSOptions{Playouts: 10})
+	assert.Error(err)
+	_, err = GenMoveLevel(g, true, CaptureFirst)
+	assert.NoError(err)
+}
+
+// ringTopology joins each point with the points at the given offsets in flat order, wrapping around.
+// As a slice, it is not comparable.
+type ringTopology []int
+
+func (r ringTopology) Neighbors(c Coord, size int) []Coord {
+	nbrs := make([]Coord, 0, len(r))
+	for _, d := range r {
+		i := ((c.X*size+c.Y+d)%(size*size) + size*size) % (size * size)
+		nbrs = append(nbrs, Coord{i / size, i % size})
+	}
+	return nbrs
+}
+
+func TestGeometryCache(t *testing.T) {
+	assert := assert.New(t)
+	assert.Same(geometryFor(5, Grid{}), geometryFor(5, Grid{}))
+	assert.Same(geometryFor(5, Torus{}), geometryFor(5, Torus{}))
+	assert.NotSame(geometryFor(5, Grid{}), geometryFor(5, Torus{}))
+
+	ring := ringTopology{-1, 1}
+	assert.NotPanics(func() { newFastBoard(3, ring) })
+	assert.NotSame(geometryFor(3, ring), geometryFor(3, ring))
+	assert.Equal([]int{8, 1}, geometryFor(3, ring).nbrs[0])
+	g, _ := NewGameWithTopology(3, Graph{{0, 0}: {{1, 1}}, {1, 1}: {{0, 0}}})
+	assert.Same(g.board.geo, g.FastBoard().geo) //computed once for the game
+	assert.Same(g.board.geo, g.Clone().FastBoard().geo)
+	geometriesMu.Lock()
+	defer geometriesMu.Unlock()
+	for key := range geometries {
+		switch key.topology.(type) {
+		case Grid, Torus, Cylinder:
+		default:
+			t.Errorf("geometry of %T cached", key.topology)
+		}
+	}
+}
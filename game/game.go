@@ -2,6 +2,7 @@ package game
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -9,15 +10,33 @@ type GoGame struct {
 	BlackPlayedLast bool
 	BlackCaptures   int
 	WhiteCaptures   int
+	CaptureTarget   int   // If positive, the game is Capture Go (Atari Go): the first player capturing this many stones wins.
+	Colors          int   // Number of players in multi-color Go, each one with its own color. 0 in games of black and white.
+	Turn            int   // Player in turn in multi-color Go, from 0 (black). Games of black and white use BlackPlayedLast.
+	Captures        []int // Captured stones of each player in multi-color Go, like BlackCaptures and WhiteCaptures.
 	board           *board
+	setup           []RecordMove //stones placed with Setup
+	history         []RecordMove //moves and passes played. In multi-color Go, Black is only true for the black player
 }
 
 func NewGame(n int) (*GoGame, error) {
-	if n <= 0 {
+	return NewGameWithTopology(n, Grid{})
+}
+
+// NewGameWithTopology creates a game where the neighbours of each point are given by the topology,
+// like a Torus or a custom Graph.
+func NewGameWithTopology(n int, t Topology) (*GoGame, error) {
+	if n <= 1 {
 		return nil, fmt.Errorf("invalid board size (%v x %v)", n, n)
 	}
+	if t == nil {
+		t = Grid{}
+	}
+	if err := validateTopology(t, n); err != nil {
+		return nil, err
+	}
 	g := GoGame{}
-	b, err := newBoard(n)
+	b, err := newBoard(n, t)
 	if err != nil {
 		return nil, err
 	}
@@ -25,6 +44,35 @@ func NewGame(n int) (*GoGame, error) {
 	return &g, nil
 }
 
+// NewCaptureGame creates a game of Capture Go, won by the first player capturing target stones.
+func NewCaptureGame(n, target int) (*GoGame, error) {
+	if target <= 0 {
+		return nil, fmt.Errorf("invalid capture target %v", target)
+	}
+	g, err := NewGame(n)
+	if err != nil {
+		return nil, err
+	}
+	g.CaptureTarget = target
+	return g, nil
+}
+
+// NewMultiColorGame creates a game of multi-color Go, where the players take turns in order: black, white, red,
+// green and yellow. Stones of every other color are opponents. FastBoard captures them too, but the rest of the
+// analysis of the position, like GenMove, Estimate or the scores, only supports black and white.
+func NewMultiColorGame(n, colors int) (*GoGame, error) {
+	if colors < 3 || colors > maxColors {
+		return nil, fmt.Errorf("invalid number of colors %v, must be between 3 and %v", colors, maxColors)
+	}
+	g, err := NewGame(n)
+	if err != nil {
+		return nil, err
+	}
+	g.Colors = colors
+	g.Captures = make([]int, colors)
+	return g, nil
+}
+
 func (g GoGame) String() string {
 	var sb strings.Builder
 	sb.WriteString(g.board.String())
@@ -32,6 +80,12 @@ func (g GoGame) String() string {
 }
 
 func (g *GoGame) Play(x, y int, black bool) error {
+	if g.Colors > 0 {
+		return g.PlayColor(x, y, playerOf(black))
+	}
+	if over, _ := g.Winner(); over {
+		return fmt.Errorf("game is over")
+	}
 	if g.BlackPlayedLast == black {
 		switch g.BlackPlayedLast {
 		case true:
@@ -40,20 +94,146 @@ func (g *GoGame) Play(x, y int, black bool) error {
 			return fmt.Errorf("invalid turn. now black must play")
 		}
 	}
-	cap, err := g.board.play(x, y, black)
+	captured, err := g.board.play(x, y, colorOf(black))
 	if err != nil {
 		return err
 	}
-	if black {
-		g.WhiteCaptures += cap
-	} else {
-		g.BlackCaptures += cap
+	g.WhiteCaptures += captured[WHITE]
+	g.BlackCaptures += captured[BLACK]
+	g.BlackPlayedLast = !g.BlackPlayedLast
+	g.history = append(g.history, RecordMove{Move: Move{X: x, Y: y}, Black: black})
+	return nil
+}
+
+// PlayColor plays a stone of the player, from 0 (black) to the number of colors of the game minus one.
+// In games of black and white it is the same as Play.
+func (g *GoGame) PlayColor(x, y int, player int) error {
+	if g.Colors == 0 {
+		if player != 0 && player != 1 {
+			return fmt.Errorf("invalid player %v", player)
+		}
+		return g.Play(x, y, player == 0)
+	}
+	if player != g.Turn {
+		return fmt.Errorf("invalid turn. now %s must play", ColorName(g.Turn))
+	}
+	captured, err := g.board.play(x, y, stateOf(player))
+	if err != nil {
+		return err
+	}
+	for state, n := range captured {
+		g.Captures[state-1] += n
+	}
+	g.BlackCaptures += captured[BLACK]
+	g.WhiteCaptures += captured[WHITE]
+	g.Turn = (g.Turn + 1) % g.Colors
+	g.history = append(g.history, RecordMove{Move: Move{X: x, Y: y}, Black: player == 0})
+	return nil
+}
+
+// Setup places a stone before the game starts, like the handicap or the stones of a problem.
+// The turn does not change and captures are not counted.
+func (g *GoGame) Setup(x, y int, black bool) error {
+	if _, err := g.board.play(x, y, colorOf(black)); err != nil {
+		return err
+	}
+	g.setup = append(g.setup, RecordMove{Move: Move{X: x, Y: y}, Black: black})
+	return nil
+}
+
+func (g *GoGame) Pass(black bool) error {
+	if g.Colors > 0 {
+		return g.PassColor(playerOf(black))
+	}
+	if over, _ := g.Winner(); over {
+		return fmt.Errorf("game is over")
+	}
+	if g.BlackPlayedLast == black {
+		switch g.BlackPlayedLast {
+		case true:
+			return fmt.Errorf("invalid turn. now white must play")
+		default:
+			return fmt.Errorf("invalid turn. now black must play")
+		}
 	}
 	g.BlackPlayedLast = !g.BlackPlayedLast
+	g.history = append(g.history, RecordMove{Move: Move{Pass: true}, Black: black})
 	return nil
 }
 
+// PassColor passes the turn of the player, from 0 (black) to the number of colors of the game minus one.
+// In games of black and white it is the same as Pass.
+func (g *GoGame) PassColor(player int) error {
+	if g.Colors == 0 {
+		if player != 0 && player != 1 {
+			return fmt.Errorf("invalid player %v", player)
+		}
+		return g.Pass(player == 0)
+	}
+	if player != g.Turn {
+		return fmt.Errorf("invalid turn. now %s must play", ColorName(g.Turn))
+	}
+	g.Turn = (g.Turn + 1) % g.Colors
+	g.history = append(g.history, RecordMove{Move: Move{Pass: true}, Black: player == 0})
+	return nil
+}
+
+// Winner reports if a Capture Go game is over, and if black is the winner.
+// Normal games, and multi-color games, are never over by captures.
+func (g *GoGame) Winner() (over bool, black bool) {
+	if g.CaptureTarget <= 0 || g.Colors > 0 {
+		return false, false
+	}
+	switch {
+	case g.WhiteCaptures >= g.CaptureTarget:
+		return true, true
+	case g.BlackCaptures >= g.CaptureTarget:
+		return true, false
+	}
+	return false, false
+}
+
+// Clone returns a deep copy of the game, fully independent of the original.
+// For search, GoGame.FastBoard returns a cheaper copy of the position.
+func (g *GoGame) Clone() *GoGame {
+	c := *g
+	c.Captures = slices.Clone(g.Captures)
+	c.setup = slices.Clone(g.setup)
+	c.history = slices.Clone(g.history)
+	c.board = g.board.clone()
+	return &c
+}
+
+// LastMove returns the last move played, which can be a pass. ok is false if nothing was played yet.
+func (g *GoGame) LastMove() (m Move, ok bool) {
+	if len(g.history) == 0 {
+		return Move{}, false
+	}
+	return g.history[len(g.history)-1].Move, true
+}
+
+// History returns the moves and passes played, in order. The stones placed with Setup are not included.
+// In multi-color Go the players take turns in order, and Black is only true for the moves of black.
+func (g *GoGame) History() []RecordMove {
+	return slices.Clone(g.history)
+}
+
+func (g *GoGame) Size() int {
+	return g.board.size
+}
+
+func (g *GoGame) Topology() Topology {
+	return g.board.topology
+}
+
 func (g *GoGame) Close() error {
 	g.board = nil
 	return nil
 }
+
+func playerOf(black bool) int {
+	if black {
+		return 0
+	}
+	return 1
+}
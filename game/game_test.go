@@ -13,6 +13,8 @@ func TestGameCreation(t *testing.T) {
 	assert.Error(err)
 	_, err = NewGame(-2)
 	assert.Error(err)
+	_, err = NewGame(1)
+	assert.Error(err)
 	_, err = NewGame(5)
 	assert.NoError(err)
 }
@@ -71,60 +73,61 @@ func TestChains(t *testing.T) {
 	g.Play(1, 1, true)
 	assert.Equal(1, len(g.board.chains))
 	assert.Contains(g.board.chains, 1)
-	assert.Equal(1, len(g.board.chains[1].points))
+	assert.Equal(1, len(g.board.chains[1].stones()))
 	assert.Equal(4, g.board.chains[1].liberties)
-	assert.Same(&g.board.field[1][1], g.board.chains[1].points[0])
+	assert.Same(&g.board.field[1][1], g.board.chains[1].stones()[0])
 	g.Play(1, 2, false)
 	assert.Equal(2, len(g.board.chains))
 	assert.Contains(g.board.chains, 2)
-	assert.Equal(1, len(g.board.chains[2].points))
+	assert.Equal(1, len(g.board.chains[2].stones()))
 	assert.Equal(3, g.board.chains[1].liberties)
 	assert.Equal(3, g.board.chains[2].liberties)
-	assert.Same(&g.board.field[1][2], g.board.chains[2].points[0])
+	assert.Same(&g.board.field[1][2], g.board.chains[2].stones()[0])
 	g.Play(2, 1, true)
 	assert.Equal(2, len(g.board.chains))
 	assert.Contains(g.board.chains, 1)
-	assert.Equal(2, len(g.board.chains[1].points))
+	assert.Equal(2, len(g.board.chains[1].stones()))
 	assert.Equal(5, g.board.chains[1].liberties)
-	assert.Same(&g.board.field[2][1], g.board.chains[1].points[1])
+	assert.Same(&g.board.field[2][1], g.board.chains[1].stones()[1])
 	g.Play(2, 2, false)
 	assert.Equal(2, len(g.board.chains))
 	assert.Contains(g.board.chains, 2)
-	assert.Equal(2, len(g.board.chains[2].points))
+	assert.Equal(2, len(g.board.chains[2].stones()))
 	assert.Equal(4, g.board.chains[2].liberties)
-	assert.Same(&g.board.field[2][2], g.board.chains[2].points[1])
+	assert.Same(&g.board.field[2][2], g.board.chains[2].stones()[1])
 	g.Play(3, 2, true)
 	assert.Equal(3, len(g.board.chains))
 	assert.Contains(g.board.chains, 3)
-	assert.Equal(1, len(g.board.chains[3].points))
+	assert.Equal(1, len(g.board.chains[3].stones()))
 	assert.Equal(4, g.board.chains[1].liberties)
 	assert.Equal(3, g.board.chains[2].liberties)
 	assert.Equal(3, g.board.chains[3].liberties)
-	assert.Same(&g.board.field[3][2], g.board.chains[3].points[0])
+	assert.Same(&g.board.field[3][2], g.board.chains[3].stones()[0])
 	g.Play(2, 3, false)
 	assert.Equal(3, len(g.board.chains))
 	assert.Contains(g.board.chains, 2)
-	assert.Equal(3, len(g.board.chains[2].points))
+	assert.Equal(3, len(g.board.chains[2].stones()))
 	assert.Equal(4, g.board.chains[1].liberties)
 	assert.Equal(4, g.board.chains[2].liberties)
 	assert.Equal(3, g.board.chains[3].liberties)
-	assert.Same(&g.board.field[2][3], g.board.chains[2].points[2])
+	assert.Same(&g.board.field[2][3], g.board.chains[2].stones()[2])
 	g.Play(3, 1, true)
 	assert.Equal(2, len(g.board.chains))
 	assert.Contains(g.board.chains, 1)
 	assert.NotContains(g.board.chains, 3)
-	assert.Equal(4, len(g.board.chains[1].points))
+	assert.Equal(4, len(g.board.chains[1].stones()))
 	assert.Equal(7, g.board.chains[1].liberties)
 	assert.Equal(4, g.board.chains[2].liberties)
-	assert.Same(&g.board.field[3][1], g.board.chains[1].points[2])
+	assert.Same(&g.board.field[3][1], g.board.chains[1].stones()[2])
 	g.Play(4, 4, false)
 	assert.Equal(3, len(g.board.chains))
-	assert.Contains(g.board.chains, 3)
-	assert.Equal(1, len(g.board.chains[3].points))
+	assert.NotContains(g.board.chains, 3) //ids of merged chains are never reused
+	assert.Contains(g.board.chains, 4)
+	assert.Equal(1, len(g.board.chains[4].stones()))
 	assert.Equal(7, g.board.chains[1].liberties)
 	assert.Equal(4, g.board.chains[2].liberties)
-	assert.Equal(2, g.board.chains[3].liberties)
-	assert.Same(&g.board.field[4][4], g.board.chains[3].points[0])
+	assert.Equal(2, g.board.chains[4].liberties)
+	assert.Same(&g.board.field[4][4], g.board.chains[4].stones()[0])
 }
 
 func TestWhiteCapturesMultipleBlackWithMiddleMove(t *testing.T) {
@@ -179,3 +182,103 @@ func TestNoSelfCapture(t *testing.T) {
 	err = g.Play(0, 4, true)
 	assert.Error(err)
 }
+
+func TestClone(t *testing.T) {
+	assert := assert.New(t)
+	g, _ := NewGame(5)
+	g.Play(1, 1, true)
+	g.Play(1, 2, false)
+	g.Play(2, 1, true)
+	c := g.Clone()
+	assert.Equal(g.String(), c.String())
+	assert.Equal(g.BlackPlayedLast, c.BlackPlayedLast)
+	assert.Len(c.board.chains, 2)
+	assert.Same(&c.board.field[2][1], c.board.chains[1].stones()[1])
+	assert.Same(c.board, c.board.field[0][0].board)
+	assert.Same(c.board, c.board.chains[1].board)
+	assert.NoError(c.Play(2, 2, false))
+	assert.Equal("******BW***B*************", g.String())
+	assert.Equal("******BW***BW************", c.String())
+	assert.Len(g.board.chains[2].stones(), 1)
+	assert.Len(c.board.chains[2].stones(), 2)
+	assert.NoError(g.Play(0, 0, false))
+	assert.Equal(FREE, c.board.field[0][0].State)
+}
+
+// newGameFromRows creates a game with the stones of the rows, using the same format as GoGame.String
+// (B, W and * for free points). Players pass when needed to put the stones in order.
+func newGameFromRows(t *testing.T, rows ...string) *GoGame {
+	g, err := NewGame(len(rows))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for x, row := range rows {
+		for y, s := range row {
+			if s == '*' {
+				continue
+			}
+			black := s == 'B'
+			if g.BlackPlayedLast == black {
+				g.Pass(!black)
+			}
+			if err = g.Play(x, y, black); err != nil {
+				t.Fatalf("error setting up (%v, %v): %v", x, y, err)
+			}
+		}
+	}
+	return g
+}
+
+func TestCaptureGame(t *testing.T) {
+	assert := assert.New(t)
+	_, err := NewCaptureGame(5, 0)
+	assert.Error(err)
+	g, err := NewCaptureGame(5, 1)
+	assert.NoError(err)
+	over, _ := g.Winner()
+	assert.False(over)
+	assert.NoError(g.Play(0, 1, true))
+	assert.NoError(g.Play(0, 0, false))
+	assert.NoError(g.Play(4, 4, true))
+	assert.NoError(g.Play(4, 3, false))
+	over, _ = g.Winner()
+	assert.False(over)
+	assert.NoError(g.Play(1, 0, true)) //captures (0, 0)
+	over, black := g.Winner()
+	assert.True(over)
+	assert.True(black)
+	assert.Error(g.Play(2, 2, false))
+	assert.Error(g.Pass(false))
+
+	g, _ = NewGame(5)
+	g.WhiteCaptures = 10
+	over, _ = g.Winner()
+	assert.False(over)
+}
+
+func TestMultiColorGame(t *testing.T) {
+	assert := assert.New(t)
+	_, err := NewMultiColorGame(5, 2)
+	assert.Error(err)
+	_, err = NewMultiColorGame(5, 6)
+	assert.Error(err)
+	g, err := NewMultiColorGame(5, 3)
+	assert.NoError(err)
+	assert.NoError(g.PlayColor(0, 0, 0))
+	assert.Error(g.PlayColor(4, 4, 2)) //white turn
+	assert.NoError(g.Play(4, 4, false))
+	assert.NoError(g.PlayColor(0, 1, 2))
+	assert.NoError(g.PlayColor(2, 2, 0))
+	assert.NoError(g.PassColor(1))
+	assert.NoError(g.PlayColor(1, 0, 2)) //red captures black
+	assert.Equal([]int{1, 0, 0}, g.Captures)
+	assert.Equal(1, g.BlackCaptures)
+	assert.Equal("*R***R******B***********W", g.String())
+	assert.Error(g.PlayColor(0, 0, 0)) //self-capture
+	assert.NoError(g.Validate())
+	assert.Equal(0, g.Turn)
+	c := g.Clone()
+	c.Captures[0] = 5
+	assert.Equal(1, g.Captures[0])
+	assert.Equal("red", ColorName(2))
+}
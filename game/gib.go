@@ -0,0 +1,126 @@
+package game
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// gibSize is the size of all the games of Tygem, which does not write it in its records.
+const gibSize = 19
+
+// ParseGIB reads a game record in the GIB format of Tygem. The header has lines like "\[KEY=VALUE\]": the
+// players are in GAMEBLACKNAME and GAMEWHITENAME as "name (rank)", and GAMEINFOMAIN has the komi in tenths
+// of a point in GONGJE, the kind of result in GRLT and the score in tenths of a point in ZIPSU. The game
+// has the handicap as the fourth field of the INI line, the moves in lines like "STO 0 n color x y", where
+// the color is 1 for black and 2 for white, x the column and y the row from the top, and the passes in
+// "SKI" lines. The handicap stones are placed in their usual places. The moves are replayed: an illegal one
+// is returned as an error, together with the record.
+func ParseGIB(s string) (*Record, error) {
+	r := &Record{Size: gibSize}
+	handicap := 0
+	sc := bufio.NewScanner(strings.NewReader(s))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, `\[`) {
+			key, value, _ := strings.Cut(strings.TrimSuffix(line[2:], `\]`), "=")
+			var err error
+			switch key {
+			case "GAMEBLACKNAME":
+				r.BlackPlayer, r.BlackRank = splitPlayer(value)
+			case "GAMEWHITENAME":
+				r.WhitePlayer, r.WhiteRank = splitPlayer(value)
+			case "GAMEINFOMAIN":
+				if err = gibInfo(r, value); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "INI":
+			if len(fields) < 4 {
+				return nil, fmt.Errorf("invalid line %q", line)
+			}
+			var err error
+			if handicap, err = strconv.Atoi(fields[3]); err != nil {
+				return nil, fmt.Errorf("invalid handicap in %q", line)
+			}
+		case "STO":
+			if len(fields) < 6 {
+				return nil, fmt.Errorf("invalid move %q", line)
+			}
+			color, err1 := strconv.Atoi(fields[3])
+			y, err2 := strconv.Atoi(fields[4])
+			x, err3 := strconv.Atoi(fields[5])
+			if err1 != nil || err2 != nil || err3 != nil || (color != 1 && color != 2) {
+				return nil, fmt.Errorf("invalid move %q", line)
+			}
+			if x < 0 || x >= r.Size || y < 0 || y >= r.Size {
+				return nil, fmt.Errorf("invalid position in move %q", line)
+			}
+			r.Moves = append(r.Moves, RecordMove{Move: Move{X: x, Y: y}, Black: color == 1})
+		case "SKI":
+			//the pass has no color, it is made by the player to move
+			black := handicap < 2
+			if n := len(r.Moves); n > 0 {
+				black = !r.Moves[n-1].Black
+			}
+			r.Moves = append(r.Moves, RecordMove{Move: Move{Pass: true}, Black: black})
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.setHandicap(handicap); err != nil {
+		return nil, err
+	}
+	return r.checked()
+}
+
+// gibInfo reads the komi and the result from the GAMEINFOMAIN value of a GIB record, a list of KEY:VALUE
+// pairs separated by commas.
+func gibInfo(r *Record, info string) error {
+	values := make(map[string]string)
+	for _, pair := range strings.Split(info, ",") {
+		key, value, _ := strings.Cut(pair, ":")
+		values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if v, ok := values["GONGJE"]; ok {
+		komi, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid komi %q", v)
+		}
+		r.Komi = float64(komi) / 10
+	}
+	v, ok := values["GRLT"]
+	if !ok {
+		return nil
+	}
+	kind, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("invalid result %q", v)
+	}
+	switch kind {
+	case 0, 1:
+		score, err := strconv.Atoi(values["ZIPSU"])
+		if err != nil {
+			return fmt.Errorf("invalid score %q", values["ZIPSU"])
+		}
+		r.Result = scoreResult(kind == 0, float64(score)/10)
+	case 3:
+		r.Result = "B+R"
+	case 4:
+		r.Result = "W+R"
+	case 7:
+		r.Result = "B+T"
+	case 8:
+		r.Result = "W+T"
+	}
+	return nil
+}
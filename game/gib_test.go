@@ -0,0 +1,55 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const gibSample = `\HS
+\[GAMEBLACKNAME=Ana (3D)\]
+\[GAMEWHITENAME=Bruno (5K)\]
+\[GAMEINFOMAIN=GBKIND:3,GTYPE:0,GCDT:0,GTIME:600-30-3,GRLT:1,ZIPSU:25,DUM:0,GONGJE:65,TCNT:4\]
+\HE
+\GS
+2 1 0
+INI 0 1 0 &4
+STO 0 1 1 15 3
+STO 0 2 2 3 15
+SKI 0 3
+STO 0 4 2 3 3
+\GE
+`
+
+func TestParseGIB(t *testing.T) {
+	assert := assert.New(t)
+	r, err := ParseGIB(gibSample)
+	assert.NoError(err)
+	assert.Equal(19, r.Size)
+	assert.Equal(6.5, r.Komi)
+	assert.Equal("Ana", r.BlackPlayer)
+	assert.Equal("3D", r.BlackRank)
+	assert.Equal("Bruno", r.WhitePlayer)
+	assert.Equal("5K", r.WhiteRank)
+	assert.Equal("W+2.5", r.Result)
+	assert.Equal([]RecordMove{
+		{Move: Move{X: 3, Y: 15}, Black: true},
+		{Move: Move{X: 15, Y: 3}},
+		{Move: Move{Pass: true}, Black: true},
+		{Move: Move{X: 3, Y: 3}},
+	}, r.Moves)
+
+	r, err = ParseGIB("\\[GAMEINFOMAIN=GRLT:3,GONGJE:5\\]\nINI 0 1 3 &4\nSTO 0 1 2 2 2\nSKI 0 2\n")
+	assert.NoError(err)
+	assert.Equal("B+R", r.Result)
+	assert.Equal(0.5, r.Komi)
+	assert.Len(r.BlackSetup, 3)
+	assert.Equal([]RecordMove{{Move: Move{X: 2, Y: 2}}, {Move: Move{Pass: true}, Black: true}}, r.Moves)
+
+	_, err = ParseGIB("INI 0 1 2 &4\nSTO 0 1 2 3 15\n")
+	assert.ErrorContains(err, "illegal move 1")
+	_, err = ParseGIB("STO 0 1 3 3 15\n")
+	assert.Error(err)
+	_, err = ParseGIB("STO 0 1 1 19 0\n")
+	assert.Error(err)
+}
@@ -0,0 +1,141 @@
+package game
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// BotLevel selects the rules followed by the lightweight players of GenMoveLevel.
+// Each level also applies the rules of the previous ones.
+type BotLevel int
+
+const (
+	RandomLegal    BotLevel = iota // Plays any legal move, except filling its own eyes.
+	CaptureFirst                   // Captures as many stones as possible when it can.
+	AtariAware                     // Saves its own stones in atari, or puts the opponent in atari.
+	AvoidSelfAtari                 // Never leaves the stones it plays in atari.
+)
+
+// candidate is the result of trying a move in a copy of the board.
+type candidate struct {
+	move      int
+	captured  int //stones captured by the move
+	liberties int //liberties of the chain of the move after playing it
+	saves     int //own stones in atari that are not in atari after the move
+	ataris    int //opponent chains put in atari by the move
+}
+
+// LegalMoves returns all the positions where the player can put a stone.
+func (g *GoGame) LegalMoves(black bool) []Move {
+	b := g.FastBoard()
+	c := colorOf(black)
+	moves := make([]Move, 0, len(b.empty))
+	for i := 0; i < len(b.stones); i++ {
+		if b.isLegal(i, c) {
+			moves = append(moves, Move{X: i / g.board.size, Y: i % g.board.size})
+		}
+	}
+	return moves
+}
+
+// GenMoveLevel chooses a move for the player in turn following the simple rules of the given level.
+// It passes when there are no moves left but filling its own eyes.
+func GenMoveLevel(g *GoGame, black bool, level BotLevel) (Move, error) {
+	if g.BlackPlayedLast == black {
+		return Move{}, fmt.Errorf("invalid turn. it is not the turn of the requested side")
+	}
+	if level < RandomLegal || level > AvoidSelfAtari {
+		return Move{}, fmt.Errorf("invalid bot level %v", level)
+	}
+	b := g.FastBoard()
+	c := colorOf(black)
+	cands := evaluateCandidates(b, c)
+	if level >= AvoidSelfAtari {
+		cands = filterCandidates(cands, func(cd candidate) bool { return cd.liberties > 1 || cd.captured > 0 })
+	}
+	if level >= CaptureFirst {
+		if best := bestCandidate(cands, func(cd candidate) int { return cd.captured }); best != nil {
+			return toMove(best.move, g.board.size), nil
+		}
+	}
+	if level >= AtariAware {
+		if best := bestCandidate(cands, func(cd candidate) int { return cd.saves }); best != nil {
+			return toMove(best.move, g.board.size), nil
+		}
+		if best := bestCandidate(cands, func(cd candidate) int { return cd.ataris }); best != nil {
+			return toMove(best.move, g.board.size), nil
+		}
+	}
+	if len(cands) == 0 {
+		return Move{Pass: true}, nil
+	}
+	return toMove(cands[rand.IntN(len(cands))].move, g.board.size), nil
+}
+
+func toMove(i, size int) Move {
+	return Move{X: i / size, Y: i % size}
+}
+
+// evaluateCandidates tries every legal move of color c that does not fill its own eyes.
+func evaluateCandidates(b *FastBoard, c pointStateType) []candidate {
+	inAtari := make(map[int]bool) //heads of the own chains in atari
+	for i, s := range b.stones {
+		if s == c && b.head[i] == i && b.liberties(i) == 1 {
+			inAtari[i] = true
+		}
+	}
+	cands := make([]candidate, 0, len(b.empty))
+	after := b.Clone()
+	for _, i := range b.empty {
+		if !b.isLegal(i, c) || b.isEye(i, c) {
+			continue
+		}
+		after.CopyFrom(b)
+		cd := candidate{move: i, captured: after.play(i, c)}
+		cd.liberties = after.liberties(i)
+		for h := range inAtari {
+			if after.stones[h] == c && after.liberties(h) > 1 {
+				cd.saves += after.count[after.head[h]]
+			}
+		}
+		seen := make(map[int]bool)
+		for _, n := range after.geo.nbrs[i] {
+			if after.stones[n] != FREE && after.stones[n] != c && !seen[after.head[n]] {
+				seen[after.head[n]] = true
+				if after.liberties(n) == 1 {
+					cd.ataris++
+				}
+			}
+		}
+		cands = append(cands, cd)
+	}
+	return cands
+}
+
+func filterCandidates(cands []candidate, keep func(candidate) bool) []candidate {
+	kept := make([]candidate, 0, len(cands))
+	for _, cd := range cands {
+		if keep(cd) {
+			kept = append(kept, cd)
+		}
+	}
+	return kept
+}
+
+// bestCandidate returns a random candidate among the ones with the highest positive value. nil if none is positive.
+func bestCandidate(cands []candidate, value func(candidate) int) *candidate {
+	var best []int
+	bestValue := 0
+	for k, cd := range cands {
+		v := value(cd)
+		if v > bestValue {
+			best, bestValue = []int{k}, v
+		} else if v == bestValue && v > 0 {
+			best = append(best, k)
+		}
+	}
+	if len(best) == 0 {
+		return nil
+	}
+	return &cands[best[rand.IntN(len(best))]]
+}
@@ -0,0 +1,80 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLegalMoves(t *testing.T) {
+	assert := assert.New(t)
+	g, _ := NewGame(5)
+	assert.Len(g.LegalMoves(true), 25)
+	g.Play(0, 1, true)
+	g.Play(2, 2, false)
+	g.Play(1, 0, true)
+	moves := g.LegalMoves(false)
+	assert.Len(moves, 21)
+	assert.NotContains(moves, Move{X: 0, Y: 0}) //self-capture
+	assert.NotContains(moves, Move{X: 2, Y: 2})
+	assert.Contains(g.LegalMoves(true), Move{X: 0, Y: 0})
+}
+
+func TestGenMoveLevelCapturesFirst(t *testing.T) {
+	//      * * * * *
+	//      * * B * *
+	//      * B W B *
+	//      * * * * *
+	//      * * * * W
+	assert := assert.New(t)
+	g, _ := NewGame(5)
+	g.Play(1, 2, true)
+	g.Play(2, 2, false)
+	g.Play(2, 1, true)
+	g.Play(4, 4, false)
+	g.Play(2, 3, true)
+	for i := 0; i < 10; i++ {
+		m, err := GenMoveLevel(g, false, CaptureFirst)
+		assert.NoError(err)
+		assert.Contains(g.LegalMoves(false), m)
+	}
+	//white escapes from atari
+	m, err := GenMoveLevel(g, false, AtariAware)
+	assert.NoError(err)
+	assert.Equal(Move{X: 3, Y: 2}, m)
+	g.Play(4, 0, false)
+	//black captures
+	m, err = GenMoveLevel(g, true, CaptureFirst)
+	assert.NoError(err)
+	assert.Equal(Move{X: 3, Y: 2}, m)
+	_, err = GenMoveLevel(g, false, CaptureFirst)
+	assert.Error(err)
+	_, err = GenMoveLevel(g, true, BotLevel(10))
+	assert.Error(err)
+}
+
+func TestGenMoveLevelAvoidsSelfAtari(t *testing.T) {
+	//      B * W * *
+	//      * * B * *
+	//      * B * B *
+	//      * * * * *
+	//      * * * * *
+	assert := assert.New(t)
+	g, _ := NewGame(5)
+	g.Play(1, 2, true)
+	g.Play(0, 2, false)
+	g.Play(2, 1, true)
+	g.Pass(false)
+	g.Play(2, 3, true)
+	g.Pass(false)
+	g.Play(0, 0, true)
+	for i := 0; i < 50; i++ {
+		m, err := GenMoveLevel(g, false, AvoidSelfAtari)
+		assert.NoError(err)
+		assert.False(m.Pass)
+		assert.NotEqual(Move{X: 2, Y: 2}, m)
+		b := g.FastBoard()
+		b.play(m.X*5+m.Y, WHITE)
+		assert.Greater(b.liberties(m.X*5+m.Y), 1, "white move %v is self-atari", m)
+	}
+}
@@ -0,0 +1,276 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// jsonVersion is the version of the JSON format of GoGame. Games in other versions are not read.
+const jsonVersion = 1
+
+// gameJSON is the complete state of a game. The chains keep their ids, so a restored game goes on exactly like
+// the original one. There is no ko state, as GoGame does not follow the ko rule: analysis like FastBoard
+// only looks at the position.
+type gameJSON struct {
+	Version         int         `json:"version"`
+	Size            int         `json:"size"`
+	Topology        string      `json:"topology"`        // "grid", "torus", "cylinder" or "graph".
+	Graph           []graphJSON `json:"graph,omitempty"` // Neighbours of each point, only for "graph".
+	CaptureTarget   int         `json:"captureTarget,omitempty"`
+	Colors          int         `json:"colors,omitempty"`
+	Board           []string    `json:"board"` // Rows of the board, with the letters of GoGame.String.
+	Chains          []chainJSON `json:"chains"`
+	LastChainId     int         `json:"lastChainId"`
+	BlackPlayedLast bool        `json:"blackPlayedLast"`
+	Turn            int         `json:"turn,omitempty"`
+	BlackCaptures   int         `json:"blackCaptures"`
+	WhiteCaptures   int         `json:"whiteCaptures"`
+	Captures        []int       `json:"captures,omitempty"`
+	Setup           []moveJSON  `json:"setup"`
+	History         []moveJSON  `json:"history"`
+}
+
+type moveJSON struct {
+	X     int  `json:"x"`
+	Y     int  `json:"y"`
+	Pass  bool `json:"pass,omitempty"`
+	Black bool `json:"black"`
+}
+
+type graphJSON struct {
+	Point     [2]int   `json:"point"`
+	Neighbors [][2]int `json:"neighbors"`
+}
+
+type chainJSON struct {
+	Id     int      `json:"id"`
+	Points [][2]int `json:"points"` // In the order they joined the chain.
+}
+
+// MarshalJSON encodes the whole state of the game: the rules, the board with its chains, the turn, the
+// captures and the history. Only the topologies of this package can be encoded.
+func (g *GoGame) MarshalJSON() ([]byte, error) {
+	b := g.board
+	if b == nil {
+		return nil, fmt.Errorf("the game is closed")
+	}
+	j := gameJSON{
+		Version:         jsonVersion,
+		Size:            b.size,
+		CaptureTarget:   g.CaptureTarget,
+		Colors:          g.Colors,
+		Board:           strings.Split(SplitRows(b.String(), b.size), "\n"),
+		Chains:          make([]chainJSON, 0, len(b.chains)),
+		LastChainId:     b.lastChainId,
+		BlackPlayedLast: g.BlackPlayedLast,
+		Turn:            g.Turn,
+		BlackCaptures:   g.BlackCaptures,
+		WhiteCaptures:   g.WhiteCaptures,
+		Captures:        g.Captures,
+		Setup:           toMovesJSON(g.setup),
+		History:         toMovesJSON(g.history),
+	}
+	switch t := b.topology.(type) {
+	case Grid:
+		j.Topology = "grid"
+	case Torus:
+		j.Topology = "torus"
+	case Cylinder:
+		j.Topology = "cylinder"
+	case Graph:
+		j.Topology = "graph"
+		for x := 0; x < b.size; x++ {
+			for y := 0; y < b.size; y++ {
+				n := graphJSON{Point: [2]int{x, y}, Neighbors: make([][2]int, 0)}
+				for _, c := range t[Coord{x, y}] {
+					n.Neighbors = append(n.Neighbors, [2]int{c.X, c.Y})
+				}
+				j.Graph = append(j.Graph, n)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("topology %T can not be encoded", b.topology)
+	}
+	for _, c := range b.chains {
+		cj := chainJSON{Id: c.id, Points: make([][2]int, c.count)}
+		for i, p := range c.stones() {
+			cj.Points[i] = [2]int{p.X, p.Y}
+		}
+		j.Chains = append(j.Chains, cj)
+	}
+	slices.SortFunc(j.Chains, func(a, b chainJSON) int { return a.Id - b.Id })
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON restores a game encoded by MarshalJSON, checking that its board is consistent, and that the
+// setup stones and the history reach it. The input is not trusted: boards bigger than 52 x 52 are rejected
+// before allocating them.
+func (g *GoGame) UnmarshalJSON(data []byte) error {
+	var j gameJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Version != jsonVersion {
+		return fmt.Errorf("unsupported game version %v", j.Version)
+	}
+	//checked before creating the game, which allocates size x size points
+	if j.Size < 2 || j.Size > maxSGFSize {
+		return fmt.Errorf("invalid board size %v, must be between 2 and %v", j.Size, maxSGFSize)
+	}
+	if len(j.Board) != j.Size {
+		return fmt.Errorf("the board must have %v rows", j.Size)
+	}
+	var t Topology
+	switch j.Topology {
+	case "grid":
+		t = Grid{}
+	case "torus":
+		t = Torus{}
+	case "cylinder":
+		t = Cylinder{}
+	case "graph":
+		graph := make(Graph)
+		for _, n := range j.Graph {
+			nbrs := make([]Coord, len(n.Neighbors))
+			for i, c := range n.Neighbors {
+				nbrs[i] = Coord{c[0], c[1]}
+			}
+			graph[Coord{n.Point[0], n.Point[1]}] = nbrs
+		}
+		t = graph
+	default:
+		return fmt.Errorf("invalid topology %q", j.Topology)
+	}
+	restored, err := NewGameWithTopology(j.Size, t)
+	if err != nil {
+		return err
+	}
+	if j.Colors != 0 && (j.Colors < 3 || j.Colors > maxColors || len(j.Captures) != j.Colors || j.Turn < 0 || j.Turn >= j.Colors) {
+		return fmt.Errorf("invalid multi-color game of %v colors", j.Colors)
+	}
+	if j.Colors == 0 && j.Captures != nil {
+		return fmt.Errorf("captures by color are only valid in multi-color games")
+	}
+	b := restored.board
+	colors := max(j.Colors, 2)
+	for x, row := range j.Board {
+		if len(row) != b.size {
+			return fmt.Errorf("row %v of the board must have %v points", x, b.size)
+		}
+		for y := range row {
+			state := strings.IndexByte(stateLetters, row[y])
+			if state < 0 || state > colors {
+				return fmt.Errorf("invalid point %q in (%v, %v)", row[y], x, y)
+			}
+			b.field[x][y].State = pointStateType(state)
+		}
+	}
+	for _, cj := range j.Chains {
+		if _, ok := b.chains[cj.Id]; ok || cj.Id <= 0 || cj.Id > j.LastChainId || len(cj.Points) == 0 {
+			return fmt.Errorf("invalid chain %v", cj.Id)
+		}
+		c := &chain{id: cj.Id, board: b}
+		for _, xy := range cj.Points {
+			if xy[0] < 0 || xy[0] >= b.size || xy[1] < 0 || xy[1] >= b.size {
+				return fmt.Errorf("invalid point (%v, %v) in chain %v", xy[0], xy[1], cj.Id)
+			}
+			p := &b.field[xy[0]][xy[1]]
+			if p.parent != nil {
+				return fmt.Errorf("point (%v, %v) is in two chains", p.X, p.Y)
+			}
+			c.state = p.State
+			c.link(p)
+		}
+		c.updateLiberties()
+		b.chains[c.id] = c
+	}
+	b.lastChainId = j.LastChainId
+	if err = restored.Validate(); err != nil {
+		return err
+	}
+	replay, err := replayMovesJSON(j.Size, t, j.Colors, j.Setup, j.History)
+	if err != nil {
+		return err
+	}
+	if replay.board.String() != b.String() {
+		return fmt.Errorf("the setup stones and the history do not reach the board")
+	}
+	b.checkpoint()
+	restored.CaptureTarget = j.CaptureTarget
+	restored.Colors = j.Colors
+	restored.BlackPlayedLast = j.BlackPlayedLast
+	restored.Turn = j.Turn
+	restored.BlackCaptures = j.BlackCaptures
+	restored.WhiteCaptures = j.WhiteCaptures
+	restored.Captures = j.Captures
+	restored.setup = fromMovesJSON(j.Setup)
+	restored.history = fromMovesJSON(j.History)
+	*g = *restored
+	return nil
+}
+
+// replayMovesJSON plays the setup stones and the history in a new game, to check them against the board.
+// In multi-color games, the players of the history take turns in order from black.
+func replayMovesJSON(size int, t Topology, colors int, setup, history []moveJSON) (*GoGame, error) {
+	g, err := NewGameWithTopology(size, t)
+	if err != nil {
+		return nil, err
+	}
+	if colors > 0 {
+		g.Colors = colors
+		g.Captures = make([]int, colors)
+	}
+	for _, m := range setup {
+		if m.Pass {
+			return nil, fmt.Errorf("invalid pass in the setup stones")
+		}
+		if err = g.Setup(m.X, m.Y, m.Black); err != nil {
+			return nil, fmt.Errorf("invalid setup stone (%v, %v): %v", m.X, m.Y, err)
+		}
+	}
+	if colors == 0 && len(history) > 0 && !history[0].Black {
+		g.BlackPlayedLast = true //white moves first, like in handicap games
+	}
+	for i, m := range history {
+		player := playerOf(m.Black)
+		if colors > 0 {
+			player = i % colors
+			if m.Black != (player == 0) {
+				return nil, fmt.Errorf("invalid player of move %v of the history", i+1)
+			}
+		}
+		if m.Pass {
+			err = g.PassColor(player)
+		} else {
+			err = g.PlayColor(m.X, m.Y, player)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid move %v of the history: %v", i+1, err)
+		}
+	}
+	return g, nil
+}
+
+func toMovesJSON(moves []RecordMove) []moveJSON {
+	if moves == nil {
+		return nil
+	}
+	mj := make([]moveJSON, len(moves))
+	for i, m := range moves {
+		mj[i] = moveJSON{X: m.X, Y: m.Y, Pass: m.Pass, Black: m.Black}
+	}
+	return mj
+}
+
+func fromMovesJSON(mj []moveJSON) []RecordMove {
+	if mj == nil {
+		return nil
+	}
+	moves := make([]RecordMove, len(mj))
+	for i, m := range mj {
+		moves[i] = RecordMove{Move: Move{X: m.X, Y: m.Y, Pass: m.Pass}, Black: m.Black}
+	}
+	return moves
+}
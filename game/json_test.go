@@ -0,0 +1,119 @@
+package game
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// roundTrip encodes and decodes the game, checking that the copy is encoded the same.
+func roundTrip(t *testing.T, g *GoGame) *GoGame {
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &GoGame{}
+	if err = json.Unmarshal(data, r); err != nil {
+		t.Fatal(err)
+	}
+	again, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, string(data), string(again))
+	return r
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+	g, _ := NewCaptureGame(5, 3)
+	assert.NoError(g.Setup(4, 4, true))
+	moves := []Move{{X: 0, Y: 1}, {X: 0, Y: 0}, {X: 1, Y: 0}, {Pass: true}, {X: 2, Y: 2}, {X: 3, Y: 3}}
+	for _, m := range moves {
+		if m.Pass {
+			assert.NoError(g.Pass(!g.BlackPlayedLast))
+		} else {
+			assert.NoError(g.Play(m.X, m.Y, !g.BlackPlayedLast))
+		}
+	}
+	r := roundTrip(t, g)
+	assert.Equal(g.String(), r.String())
+	assert.Equal(g.History(), r.History())
+	assert.Equal(g.setup, r.setup)
+	assert.Equal(1, r.WhiteCaptures)
+	assert.Equal(3, r.CaptureTarget)
+	assert.NoError(r.Validate())
+	assert.Equal(g.board.lastChainId, r.board.lastChainId)
+	for id, c := range g.board.chains {
+		assert.Equal(c.encode(), r.board.chains[id].encode())
+	}
+
+	//both games go on the same way
+	for _, m := range []Move{{X: 3, Y: 4}, {X: 2, Y: 3}, {X: 4, Y: 3}} {
+		black := !g.BlackPlayedLast
+		assert.Equal(g.Play(m.X, m.Y, black), r.Play(m.X, m.Y, black))
+	}
+	assert.Equal(g.String(), r.String())
+	assert.NoError(r.Validate())
+	a, _ := json.Marshal(g)
+	b, _ := json.Marshal(r)
+	assert.JSONEq(string(a), string(b))
+}
+
+func TestJSONVariants(t *testing.T) {
+	assert := assert.New(t)
+	m, _ := NewMultiColorGame(5, 3)
+	assert.NoError(m.PlayColor(0, 0, 0))
+	assert.NoError(m.PlayColor(0, 1, 1))
+	r := roundTrip(t, m)
+	assert.Equal(3, r.Colors)
+	assert.Equal(2, r.Turn)
+	assert.Error(r.PlayColor(1, 1, 0))
+	assert.NoError(r.PlayColor(1, 1, 2))
+
+	torus, _ := NewGameWithTopology(4, Torus{})
+	assert.NoError(torus.Play(0, 0, true))
+	assert.Equal(Torus{}, roundTrip(t, torus).Topology())
+
+	graph := Graph{{0, 0}: {{1, 1}}, {1, 1}: {{0, 0}}}
+	sparse, _ := NewGameWithTopology(2, graph)
+	assert.NoError(sparse.Play(0, 0, true))
+	r = roundTrip(t, sparse)
+	assert.Error(r.Play(0, 1, false))
+	assert.NoError(r.Play(1, 1, false))
+	assert.Equal("***W", r.String()) //the only liberty of black was taken
+	assert.Equal(1, r.BlackCaptures)
+}
+
+func TestJSONErrors(t *testing.T) {
+	assert := assert.New(t)
+	g, _ := NewGame(3)
+	assert.NoError(g.Play(1, 1, true))
+	data, _ := json.Marshal(g)
+	valid := string(data)
+	for _, c := range []struct{ old, new string }{
+		{`"version":1`, `"version":2`},
+		{`"topology":"grid"`, `"topology":"hexagonal"`},
+		{`"*B*"`, `"*BB"`},
+		{`"*B*"`, `"*X*"`},
+		{`"*B*"`, `"*R*"`},
+		{`"*B*"`, `"**"`},
+		{`"points":[[1,1]]`, `"points":[[1,2]]`},
+		{`"points":[[1,1]]`, `"points":[[1,1],[1,1]]`},
+		{`"lastChainId":1`, `"lastChainId":0`},
+		{`"size":3`, `"size":100000`},
+		{`"size":3`, `"size":4`},
+		{`"board":["***","*B*","***"]`, `"board":[]`},
+		{`{"x":1,"y":1,"black":true}`, `{"x":40,"y":40,"black":true}`},
+		{`{"x":1,"y":1,"black":true}`, `{"x":0,"y":0,"black":true}`},
+		{`{"x":1,"y":1,"black":true}`, `{"x":1,"y":1,"black":false}`},
+		{`"setup":null`, `"setup":[{"x":0,"y":0,"pass":true,"black":true}]`},
+		{`"whiteCaptures":0`, `"whiteCaptures":0,"captures":[0,0]`},
+	} {
+		assert.Contains(valid, c.old)
+		r := &GoGame{}
+		assert.Error(json.Unmarshal([]byte(strings.Replace(valid, c.old, c.new, 1)), r), c.new)
+	}
+}
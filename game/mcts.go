@@ -0,0 +1,208 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Move is an action of a player: a stone placed in (X, Y) or a pass.
+type Move struct {
+	X, Y int
+	Pass bool
+}
+
+// MCTSOptions configures the Monte Carlo tree search used by GenMove.
+type MCTSOptions struct {
+	Playouts int           // Playouts to run for the move. Ignored if Time is not zero. Defaults to 1000.
+	Time     time.Duration // Thinking time for the move.
+	Threads  int           // Goroutines searching in parallel. Defaults to 1.
+	Komi     float64       // Points added to the white score.
+}
+
+const (
+	defaultPlayouts = 1000
+	uctExploration  = 1.0
+	passMove        = -1
+)
+
+type mctsNode struct {
+	move     int  //flat index of the move leading to this node, or passMove
+	black    bool //true if the move was played by black
+	parent   *mctsNode
+	children []*mctsNode
+	untried  []int
+	visits   int
+	wins     float64 //wins for the player of the move
+}
+
+// GenMove searches the best move for the player in turn with Monte Carlo tree search (UCT) and random playouts.
+// black must be the side in turn. Each thread builds its own tree from the position and the visits of the
+// first moves are added up at the end.
+func GenMove(g *GoGame, black bool, opts MCTSOptions) (Move, error) {
+	if g.BlackPlayedLast == black {
+		return Move{}, fmt.Errorf("invalid turn. it is not the turn of the requested side")
+	}
+	if g.Colors > 0 {
+		return Move{}, fmt.Errorf("invalid game. the search only supports black and white")
+	}
+	if opts.Threads < 1 {
+		opts.Threads = 1
+	}
+	if opts.Playouts < 1 && opts.Time == 0 {
+		opts.Playouts = defaultPlayouts
+	}
+	root := g.FastBoard()
+	color := colorOf(black)
+	var deadline time.Time
+	if opts.Time > 0 {
+		deadline = time.Now().Add(opts.Time)
+	}
+	visits := make(map[int]int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for t := 0; t < opts.Threads; t++ {
+		playouts := opts.Playouts / opts.Threads
+		if t < opts.Playouts%opts.Threads {
+			playouts++
+		}
+		wg.Add(1)
+		go func(seed uint64, playouts int) {
+			defer wg.Done()
+			tree := searchTree(root, color, playouts, deadline, opts.Komi, rand.New(rand.NewPCG(seed, uint64(time.Now().UnixNano()))))
+			mu.Lock()
+			for _, c := range tree.children {
+				visits[c.move] += c.visits
+			}
+			mu.Unlock()
+		}(uint64(t), playouts)
+	}
+	wg.Wait()
+	best, bestVisits := passMove, -1
+	for m, v := range visits {
+		if v > bestVisits || (v == bestVisits && m < best) {
+			best, bestVisits = m, v
+		}
+	}
+	if best == passMove {
+		return Move{Pass: true}, nil
+	}
+	return Move{X: best / g.board.size, Y: best % g.board.size}, nil
+}
+
+// searchTree runs the UCT iterations from position b, with color c in turn, and returns the root node.
+// It stops after the given playouts, or at deadline if it is not zero.
+func searchTree(b *FastBoard, c pointStateType, playouts int, deadline time.Time, komi float64, r *rand.Rand) *mctsNode {
+	root := &mctsNode{move: passMove, black: c != BLACK, untried: candidateMoves(b, c)}
+	scratch := b.Clone()
+	for i := 0; ; i++ {
+		if deadline.IsZero() {
+			if i >= playouts {
+				break
+			}
+		} else if i%16 == 0 && time.Now().After(deadline) {
+			break
+		}
+		scratch.CopyFrom(b)
+		node := root
+		turn := c
+		//selection
+		for len(node.untried) == 0 && len(node.children) > 0 {
+			node = node.selectChild()
+			scratch.playMove(node.move, turn)
+			turn = opponent(turn)
+		}
+		//expansion
+		if len(node.untried) > 0 {
+			k := r.IntN(len(node.untried))
+			m := node.untried[k]
+			node.untried[k] = node.untried[len(node.untried)-1]
+			node.untried = node.untried[:len(node.untried)-1]
+			scratch.playMove(m, turn)
+			child := &mctsNode{move: m, black: turn == BLACK, parent: node}
+			turn = opponent(turn)
+			child.untried = candidateMoves(scratch, turn)
+			node.children = append(node.children, child)
+			node = child
+		}
+		//simulation
+		blackWins := scratch.playout(turn, komi, r) > 0
+		//backpropagation
+		for ; node != nil; node = node.parent {
+			node.visits++
+			if node.black == blackWins {
+				node.wins++
+			}
+		}
+	}
+	return root
+}
+
+func (n *mctsNode) selectChild() *mctsNode {
+	var best *mctsNode
+	bestValue := math.Inf(-1)
+	logVisits := math.Log(float64(n.visits))
+	for _, c := range n.children {
+		v := c.wins/float64(c.visits) + uctExploration*math.Sqrt(logVisits/float64(c.visits))
+		if v > bestValue {
+			best, bestValue = c, v
+		}
+	}
+	return best
+}
+
+// candidateMoves returns the legal moves of color c that do not fill its own eyes,
+// or only a pass if there is none.
+func candidateMoves(b *FastBoard, c pointStateType) []int {
+	moves := make([]int, 0, len(b.empty))
+	for _, i := range b.empty {
+		if b.isLegal(i, c) && !b.isEye(i, c) {
+			moves = append(moves, i)
+		}
+	}
+	if len(moves) == 0 {
+		moves = append(moves, passMove)
+	}
+	return moves
+}
+
+func (b *FastBoard) playMove(m int, c pointStateType) {
+	if m == passMove {
+		b.pass()
+		return
+	}
+	b.play(m, c)
+}
+
+// randomMove picks a random legal move of color c that does not fill its own eyes. passMove if there is none.
+func (b *FastBoard) randomMove(c pointStateType, r *rand.Rand) int {
+	if len(b.empty) == 0 {
+		return passMove
+	}
+	start := r.IntN(len(b.empty))
+	for k := range b.empty {
+		i := b.empty[(start+k)%len(b.empty)]
+		if b.isLegal(i, c) && !b.isEye(i, c) {
+			return i
+		}
+	}
+	return passMove
+}
+
+// playout plays random moves from color c until both players pass and returns the final area score.
+func (b *FastBoard) playout(c pointStateType, komi float64, r *rand.Rand) float64 {
+	passes := 0
+	for moves := 0; passes < 2 && moves < 3*len(b.stones); moves++ {
+		m := b.randomMove(c, r)
+		if m == passMove {
+			passes++
+		} else {
+			passes = 0
+		}
+		b.playMove(m, c)
+		c = opponent(c)
+	}
+	return b.areaScore(komi)
+}
@@ -0,0 +1,33 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenMoveCapturesInAtari(t *testing.T) {
+	//      * * * * *
+	//      * * B * *
+	//      * B W B *
+	//      * * * * *
+	//      * * * * *
+	assert := assert.New(t)
+	g, _ := NewGame(5)
+	g.Play(1, 2, true)
+	g.Play(2, 2, false)
+	g.Play(2, 1, true)
+	g.Play(4, 4, false)
+	g.Play(2, 3, true)
+	g.Play(4, 0, false)
+	m, err := GenMove(g, true, MCTSOptions{Playouts: 2000, Threads: 2})
+	assert.NoError(err)
+	assert.Equal(Move{X: 3, Y: 2}, m)
+	assert.NoError(g.Play(m.X, m.Y, true))
+	_, err = GenMove(g, true, MCTSOptions{})
+	assert.Error(err)
+	m, err = GenMove(g, false, MCTSOptions{Time: 50 * time.Millisecond})
+	assert.NoError(err)
+	assert.NoError(g.Play(m.X, m.Y, false))
+}
@@ -0,0 +1,101 @@
+package game
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NGF header lines, counted from 0.
+const (
+	ngfSizeLine     = 1
+	ngfWhiteLine    = 2 //name and rank
+	ngfBlackLine    = 3
+	ngfHandicapLine = 5
+	ngfKomiLine     = 7
+	ngfResultLine   = 10 //like "White wins by 2.5!" or "Black wins by resignation!"
+	ngfHeaderLines  = 12
+)
+
+var ngfScore = regexp.MustCompile(`\d+(\.\d+)?`)
+
+// ParseNGF reads a game record in the NGF format of WBaduk and Oro. The file starts with a header of a value
+// per line: the size, the players, the handicap, the komi and the result in words, among others. Then each
+// move is in a line like "PMABBQDQD", where the fifth character is the color and the next two are the column
+// and the row from the top, as letters from B. Points outside the board are passes. The handicap stones are
+// placed in their usual places. The moves are replayed: an illegal one is returned as an error, together with
+// the record.
+func ParseNGF(s string) (*Record, error) {
+	lines := strings.Split(s, "\n")
+	if len(lines) < ngfHeaderLines {
+		return nil, fmt.Errorf("the header of the record must have %v lines", ngfHeaderLines)
+	}
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	r := &Record{}
+	var err error
+	if r.Size, err = strconv.Atoi(lines[ngfSizeLine]); err != nil {
+		return nil, fmt.Errorf("invalid board size %q", lines[ngfSizeLine])
+	}
+	if err = r.checkSize(); err != nil {
+		return nil, err
+	}
+	r.WhitePlayer, r.WhiteRank = splitPlayer(lines[ngfWhiteLine])
+	r.BlackPlayer, r.BlackRank = splitPlayer(lines[ngfBlackLine])
+	handicap, err := strconv.Atoi(lines[ngfHandicapLine])
+	if err != nil {
+		return nil, fmt.Errorf("invalid handicap %q", lines[ngfHandicapLine])
+	}
+	if r.Komi, err = strconv.ParseFloat(lines[ngfKomiLine], 64); err != nil {
+		return nil, fmt.Errorf("invalid komi %q", lines[ngfKomiLine])
+	}
+	r.Result = ngfResult(lines[ngfResultLine])
+	for _, line := range lines[ngfHeaderLines:] {
+		if !strings.HasPrefix(line, "PM") {
+			continue
+		}
+		if len(line) < 7 || (line[4] != 'B' && line[4] != 'W') {
+			return nil, fmt.Errorf("invalid move %q", line)
+		}
+		y, x := int(line[5])-'B', int(line[6])-'B'
+		m := Move{X: x, Y: y}
+		if x < 0 || x >= r.Size || y < 0 || y >= r.Size {
+			m = Move{Pass: true}
+		}
+		r.Moves = append(r.Moves, RecordMove{Move: m, Black: line[4] == 'B'})
+	}
+	if err = r.setHandicap(handicap); err != nil {
+		return nil, err
+	}
+	return r.checked()
+}
+
+// ngfResult converts the result of an NGF record, written in English, to SGF notation. Results that are not
+// understood are left empty.
+func ngfResult(s string) string {
+	s = strings.ToLower(s)
+	var winner string
+	switch {
+	case strings.Contains(s, "black win"):
+		winner = "B"
+	case strings.Contains(s, "white win"):
+		winner = "W"
+	case strings.Contains(s, "jigo") || strings.Contains(s, "draw"):
+		return "0"
+	default:
+		return ""
+	}
+	switch {
+	case strings.Contains(s, "resign"):
+		return winner + "+R"
+	case strings.Contains(s, "time"):
+		return winner + "+T"
+	}
+	if score := ngfScore.FindString(s); score != "" {
+		v, _ := strconv.ParseFloat(score, 64)
+		return scoreResult(winner == "B", v)
+	}
+	return winner + "+"
+}
@@ -0,0 +1,49 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNGF(t *testing.T) {
+	assert := assert.New(t)
+	header := []string{"Rated game", "9", "Bruno 2D*", "Ana 1K", "www.example.com", "0", "0", "6.5", "2024-05-01", "0", "White wins by resignation!", "3"}
+	r, err := ParseNGF(strings.Join(append(header, "PMABBFFFF", "PMACWDEDE", "PMADBAAAA"), "\r\n"))
+	assert.NoError(err)
+	assert.Equal(9, r.Size)
+	assert.Equal(6.5, r.Komi)
+	assert.Equal("Ana", r.BlackPlayer)
+	assert.Equal("1K", r.BlackRank)
+	assert.Equal("Bruno", r.WhitePlayer)
+	assert.Equal("2D", r.WhiteRank)
+	assert.Equal("W+R", r.Result)
+	assert.Equal([]RecordMove{
+		{Move: Move{X: 4, Y: 4}, Black: true},
+		{Move: Move{X: 3, Y: 2}},
+		{Move: Move{Pass: true}, Black: true},
+	}, r.Moves)
+
+	header[1], header[5] = "19", "2"
+	r, err = ParseNGF(strings.Join(append(header, "PMABWDDDD"), "\n"))
+	assert.NoError(err)
+	assert.Equal([]Coord{{3, 15}, {15, 3}}, r.BlackSetup)
+
+	_, err = ParseNGF(strings.Join(append(header, "PMABWEQEQ"), "\n"))
+	assert.ErrorContains(err, "illegal move 1")
+	_, err = ParseNGF(strings.Join(header[:5], "\n"))
+	assert.Error(err)
+	header[1] = "60000"
+	_, err = ParseNGF(strings.Join(header, "\n"))
+	assert.ErrorContains(err, "invalid board size")
+
+	for s, result := range map[string]string{
+		"Black wins by 12.5 points!": "B+12.5",
+		"White wins on time!":        "W+T",
+		"Jigo":                       "0",
+		"Unfinished":                 "",
+	} {
+		assert.Equal(result, ngfResult(s), s)
+	}
+}
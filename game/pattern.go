@@ -0,0 +1,337 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Pattern is a local shape around a move, given by 3 or 5 rows of 3 or 5 points with the move in the center.
+// Colors are relative to the player of the move, so the same pattern matches the moves of black and white:
+//
+//	X  a stone of the player
+//	O  a stone of the opponent
+//	.  an empty point. The center is always empty
+//	#  outside the board
+//	x  anything but a stone of the player, inside the board
+//	o  anything but a stone of the opponent, inside the board
+//	?  anything, even outside the board
+//
+// Patterns match in any of the eight orientations of the board.
+type Pattern struct {
+	Name string
+	Rows []string
+}
+
+// Codes of the points around a move, relative to the player.
+const (
+	cellEmpty uint8 = iota
+	cellOwn
+	cellOpponent
+	cellEdge
+)
+
+var cellMasks = map[byte]uint8{
+	'.': 1 << cellEmpty,
+	'X': 1 << cellOwn,
+	'O': 1 << cellOpponent,
+	'#': 1 << cellEdge,
+	'x': 1<<cellEmpty | 1<<cellOpponent,
+	'o': 1<<cellEmpty | 1<<cellOwn,
+	'?': 1<<cellEmpty | 1<<cellOwn | 1<<cellOpponent | 1<<cellEdge,
+}
+
+const anyCell = 1<<cellEmpty | 1<<cellOwn | 1<<cellOpponent | 1<<cellEdge
+
+// patternCell is a point of a 5x5 pattern that is not '?', relative to the move.
+type patternCell struct {
+	dx, dy int
+	mask   uint8
+}
+
+// largePattern is an orientation of a 5x5 pattern.
+type largePattern struct {
+	id    int
+	cells []patternCell
+}
+
+// PatternLibrary matches moves against a set of patterns. The 3x3 patterns are precomputed in a table
+// indexed by the eight points around the move, so they are matched with a single lookup; the 5x5 patterns are
+// checked point by point.
+type PatternLibrary struct {
+	names []string
+	small [][]int //3x3 neighbourhood code to the patterns matching it, nil if there are no 3x3 patterns
+	large []largePattern
+}
+
+// standardPatterns are basic shapes used in teaching.
+const standardPatterns = `
+// Bending around the head of an opponent stone in contact with a stone of the player.
+hane
+???
+?.O
+?.X
+
+// Three stones in an L with the fourth point of the square empty, a famous bad shape. The move can be the
+// corner of the L or one of its ends.
+empty-triangle
+???
+?.X
+?X.
+
+empty-triangle
+???
+?.X
+?.X
+
+// Three stones around an empty point, protecting it like the mouth of a tiger. The move can be any of them.
+tigers-mouth
+??X
+?..
+??X
+
+tigers-mouth
+?????
+???X?
+??..X
+?????
+?????
+
+// Jumping one point from a stone of the player, with no stones close to the jump.
+one-point-jump
+??.??
+?...?
+X...?
+?...?
+??.??
+`
+
+var standardLibrary = sync.OnceValue(func() *PatternLibrary {
+	l, err := ParsePatterns(standardPatterns)
+	if err != nil {
+		panic(err)
+	}
+	return l
+})
+
+// StandardPatterns returns a library with a few well known shapes: hane, empty-triangle, tigers-mouth and
+// one-point-jump.
+func StandardPatterns() *PatternLibrary {
+	return standardLibrary()
+}
+
+// ParsePatterns reads a library in text format: each pattern is a line with its name followed by its rows.
+// Spaces inside the rows, blank lines and lines starting with // are ignored.
+func ParsePatterns(src string) (*PatternLibrary, error) {
+	patterns := make([]Pattern, 0)
+	var current *Pattern
+	for n, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		if current == nil || len(current.Rows) > 0 && len(current.Rows) == len(current.Rows[0]) {
+			patterns = append(patterns, Pattern{Name: line})
+			current = &patterns[len(patterns)-1]
+			continue
+		}
+		current.Rows = append(current.Rows, strings.ReplaceAll(line, " ", ""))
+		if len(current.Rows) > 5 {
+			return nil, fmt.Errorf("line %v: pattern %s has too many rows", n+1, current.Name)
+		}
+	}
+	return NewPatternLibrary(patterns)
+}
+
+// NewPatternLibrary builds a library with the patterns, which are reported in the given order. Patterns with
+// the same name are alternative forms of the same shape.
+func NewPatternLibrary(patterns []Pattern) (*PatternLibrary, error) {
+	l := &PatternLibrary{names: make([]string, 0, len(patterns))}
+	for _, p := range patterns {
+		masks, err := p.masks()
+		if err != nil {
+			return nil, err
+		}
+		id := slices.Index(l.names, p.Name)
+		if id < 0 {
+			id = len(l.names)
+			l.names = append(l.names, p.Name)
+		}
+		for _, variant := range patternVariants(masks) {
+			if len(variant) == 3 {
+				l.addSmall(id, variant)
+			} else {
+				l.addLarge(id, variant)
+			}
+		}
+	}
+	return l, nil
+}
+
+// masks returns the codes allowed in each point of the pattern.
+func (p Pattern) masks() ([][]uint8, error) {
+	size := len(p.Rows)
+	if size != 3 && size != 5 {
+		return nil, fmt.Errorf("pattern %s must have 3 or 5 rows", p.Name)
+	}
+	masks := make([][]uint8, size)
+	for x, row := range p.Rows {
+		if len(row) != size {
+			return nil, fmt.Errorf("pattern %s must have as many columns as rows", p.Name)
+		}
+		masks[x] = make([]uint8, size)
+		for y := 0; y < size; y++ {
+			m, ok := cellMasks[row[y]]
+			if !ok {
+				return nil, fmt.Errorf("invalid character %q in pattern %s", row[y], p.Name)
+			}
+			masks[x][y] = m
+		}
+	}
+	if c := size / 2; p.Rows[c][c] != '.' {
+		return nil, fmt.Errorf("the center of pattern %s must be empty", p.Name)
+	}
+	return masks, nil
+}
+
+// patternVariants returns the different orientations of the pattern.
+func patternVariants(masks [][]uint8) [][][]uint8 {
+	size := len(masks)
+	variants := make([][][]uint8, 0, len(Symmetries))
+	for _, s := range Symmetries {
+		v := make([][]uint8, size)
+		for x := range v {
+			v[x] = make([]uint8, size)
+		}
+		for x := range masks {
+			for y := range masks[x] {
+				c := s.Coord(Coord{x, y}, size)
+				v[c.X][c.Y] = masks[x][y]
+			}
+		}
+		if !slices.ContainsFunc(variants, func(o [][]uint8) bool { return slices.EqualFunc(o, v, slices.Equal) }) {
+			variants = append(variants, v)
+		}
+	}
+	return variants
+}
+
+// neighbourhood lists the eight points around a move, in the order of the bits of the 3x3 table keys.
+var neighbourhood = [8][2]int{{-1, -1}, {-1, 0}, {-1, 1}, {0, -1}, {0, 1}, {1, -1}, {1, 0}, {1, 1}}
+
+// addSmall registers the pattern in every entry of the table that the 3x3 variant matches.
+func (l *PatternLibrary) addSmall(id int, variant [][]uint8) {
+	if l.small == nil {
+		l.small = make([][]int, 1<<16)
+	}
+	var fill func(k int, key int)
+	fill = func(k int, key int) {
+		if k == len(neighbourhood) {
+			if !slices.Contains(l.small[key], id) {
+				l.small[key] = append(l.small[key], id)
+			}
+			return
+		}
+		d := neighbourhood[k]
+		mask := variant[1+d[0]][1+d[1]]
+		for code := cellEmpty; code <= cellEdge; code++ {
+			if mask&(1<<code) != 0 {
+				fill(k+1, key|int(code)<<(2*k))
+			}
+		}
+	}
+	fill(0, 0)
+}
+
+func (l *PatternLibrary) addLarge(id int, variant [][]uint8) {
+	lp := largePattern{id: id}
+	for x := range variant {
+		for y := range variant[x] {
+			if variant[x][y] != anyCell && (x != 2 || y != 2) {
+				lp.cells = append(lp.cells, patternCell{x - 2, y - 2, variant[x][y]})
+			}
+		}
+	}
+	l.large = append(l.large, lp)
+}
+
+// Match returns the names of the patterns formed by a stone of the player in (x, y), which must be empty.
+// Patterns are read on the square grid: outside the board is the edge, even on boards that wrap around.
+func (l *PatternLibrary) Match(g *GoGame, x, y int, black bool) []string {
+	b := g.FastBoard()
+	if !b.inside(x, y) || b.At(x, y) != FREE {
+		return nil
+	}
+	return l.namesOf(l.match(b, x, y, colorOf(black)))
+}
+
+// MatchMoves returns the patterns formed by every legal move of the player, leaving out the moves that
+// match none.
+func (l *PatternLibrary) MatchMoves(g *GoGame, black bool) map[Move][]string {
+	b := g.FastBoard()
+	c := colorOf(black)
+	size := b.geo.size
+	found := make(map[Move][]string)
+	for i := range b.stones {
+		if !b.isLegal(i, c) {
+			continue
+		}
+		if ids := l.match(b, i/size, i%size, c); len(ids) > 0 {
+			found[toMove(i, size)] = l.namesOf(ids)
+		}
+	}
+	return found
+}
+
+// match returns the ids of the patterns matching a move of color c in (x, y), in the order of the library.
+func (l *PatternLibrary) match(b *FastBoard, x, y int, c pointStateType) []int {
+	var ids []int
+	if l.small != nil {
+		key := 0
+		for k, d := range neighbourhood {
+			key |= int(b.patternCode(x+d[0], y+d[1], c)) << (2 * k)
+		}
+		ids = slices.Clone(l.small[key])
+	}
+	for _, lp := range l.large {
+		if slices.Contains(ids, lp.id) {
+			continue
+		}
+		matched := true
+		for _, cell := range lp.cells {
+			if cell.mask&(1<<b.patternCode(x+cell.dx, y+cell.dy, c)) == 0 {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			ids = append(ids, lp.id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func (l *PatternLibrary) namesOf(ids []int) []string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = l.names[id]
+	}
+	return names
+}
+
+// patternCode returns the code of the point (x, y) for the player of color c.
+func (b *FastBoard) patternCode(x, y int, c pointStateType) uint8 {
+	if !b.inside(x, y) {
+		return cellEdge
+	}
+	switch b.At(x, y) {
+	case FREE:
+		return cellEmpty
+	case c:
+		return cellOwn
+	default:
+		return cellOpponent
+	}
+}
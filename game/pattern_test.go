@@ -0,0 +1,130 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStandardPatterns(t *testing.T) {
+	assert := assert.New(t)
+	g := newGameFromRows(t,
+		"*******",
+		"*******",
+		"*******",
+		"****B**",
+		"***B***",
+		"*******",
+		"*******",
+	)
+	l := StandardPatterns()
+	assert.Contains(l.Match(g, 3, 3, true), "empty-triangle")
+	assert.Contains(l.Match(g, 4, 4, true), "empty-triangle")
+	assert.NotContains(l.Match(g, 3, 3, false), "empty-triangle")
+	assert.Equal([]string{"tigers-mouth"}, l.Match(g, 5, 4, true))
+	assert.Nil(l.Match(g, 3, 4, true))
+	assert.Nil(l.Match(g, 9, 9, true))
+
+	//the same shape with the colors swapped matches for white, in any orientation
+	w := newGameFromRows(t,
+		"*******",
+		"*******",
+		"**W****",
+		"***W***",
+		"*******",
+		"*******",
+		"*******",
+	)
+	assert.Contains(l.Match(w, 2, 3, false), "empty-triangle")
+	assert.Contains(l.Match(w, 3, 2, false), "empty-triangle")
+	assert.NotContains(l.Match(w, 2, 3, true), "empty-triangle")
+
+	//the move can also be an end of the L
+	e := newGameFromRows(t,
+		"*******",
+		"*******",
+		"*******",
+		"***BB**",
+		"*******",
+		"*******",
+		"*******",
+	)
+	assert.Contains(l.Match(e, 2, 4, true), "empty-triangle")
+	assert.Contains(l.Match(e, 4, 3, true), "empty-triangle")
+	assert.NotContains(l.Match(e, 2, 2, true), "empty-triangle")
+	assert.NoError(e.Setup(2, 3, false))
+	assert.NotContains(l.Match(e, 2, 4, true), "empty-triangle") //the fourth point is taken
+
+	h := newGameFromRows(t,
+		"*******",
+		"*******",
+		"*******",
+		"***W***",
+		"***B***",
+		"*******",
+		"*******",
+	)
+	assert.Equal([]string{"hane"}, l.Match(h, 3, 2, true))
+	assert.Equal([]string{"hane"}, l.Match(h, 3, 4, true))
+	assert.Equal([]string{"hane"}, l.Match(h, 4, 4, false))
+	assert.Contains(l.Match(h, 1, 3, false), "one-point-jump")
+}
+
+func TestMatchMoves(t *testing.T) {
+	assert := assert.New(t)
+	g := newGameFromRows(t,
+		"*****",
+		"*****",
+		"**B**",
+		"*****",
+		"*****",
+	)
+	l, err := ParsePatterns(`
+// the second line of the board, under an empty point
+second-line
+# # #
+. . .
+? ? ?
+
+diagonal
+X??
+?.?
+???
+`)
+	assert.NoError(err)
+	moves := l.MatchMoves(g, true)
+	assert.Equal(map[Move][]string{
+		{X: 0, Y: 1}: {"second-line"}, {X: 0, Y: 2}: {"second-line"}, {X: 0, Y: 3}: {"second-line"},
+		{X: 1, Y: 0}: {"second-line"}, {X: 2, Y: 0}: {"second-line"}, {X: 3, Y: 0}: {"second-line"},
+		{X: 4, Y: 1}: {"second-line"}, {X: 4, Y: 2}: {"second-line"}, {X: 4, Y: 3}: {"second-line"},
+		{X: 1, Y: 4}: {"second-line"}, {X: 2, Y: 4}: {"second-line"}, {X: 3, Y: 4}: {"second-line"},
+		{X: 1, Y: 1}: {"diagonal"}, {X: 1, Y: 3}: {"diagonal"}, {X: 3, Y: 1}: {"diagonal"}, {X: 3, Y: 3}: {"diagonal"},
+	}, moves)
+	assert.Empty(l.MatchMoves(g, false)[Move{X: 1, Y: 1}])
+
+	for _, src := range []string{
+		"big\n....\n....\n....\n....",
+		"bad\n...\n.Z.\n...",
+		"stone\n...\n.X.\n...",
+		"short\n...\n...",
+	} {
+		_, err = ParsePatterns(src)
+		assert.Error(err, src)
+	}
+}
+
+func BenchmarkMatchMoves(b *testing.B) {
+	g, _ := NewGame(19)
+	for i := 0; i < 120; i++ {
+		m, err := GenMoveLevel(g, !g.BlackPlayedLast, RandomLegal)
+		if err != nil || m.Pass {
+			break
+		}
+		g.Play(m.X, m.Y, !g.BlackPlayedLast)
+	}
+	l := StandardPatterns()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.MatchMoves(g, !g.BlackPlayedLast)
+	}
+}
@@ -0,0 +1,72 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Phantom is a game of Phantom Go (blind Go), where each player only sees their own stones, and the
+// opponent stones found when trying to play on them.
+type Phantom struct {
+	Game     *GoGame
+	revealed map[bool]map[Coord]bool //opponent stones revealed to each player, black is true
+}
+
+func NewPhantom(g *GoGame) *Phantom {
+	return &Phantom{
+		Game:     g,
+		revealed: map[bool]map[Coord]bool{true: make(map[Coord]bool), false: make(map[Coord]bool)},
+	}
+}
+
+// Play plays the move and returns the number of stones captured by it. An attempt on a point with an
+// opponent stone is illegal and reveals that stone to the player.
+func (p *Phantom) Play(x, y int, black bool) (int, error) {
+	g := p.Game
+	opponent := WHITE
+	if !black {
+		opponent = BLACK
+	}
+	if x >= 0 && x < g.board.size && y >= 0 && y < g.board.size && g.BlackPlayedLast != black &&
+		g.board.field[x][y].State == opponent {
+		p.revealed[black][Coord{x, y}] = true
+		return 0, fmt.Errorf("invalid position (%v, %v), there is an opponent stone", x, y)
+	}
+	before := g.BlackCaptures + g.WhiteCaptures
+	if err := g.Play(x, y, black); err != nil {
+		return 0, err
+	}
+	//captured stones are not revealed anymore, even if the point is taken again later
+	for side, revealed := range p.revealed {
+		state := BLACK
+		if side {
+			state = WHITE
+		}
+		for c := range revealed {
+			if g.board.field[c.X][c.Y].State != state {
+				delete(revealed, c)
+			}
+		}
+	}
+	return g.BlackCaptures + g.WhiteCaptures - before, nil
+}
+
+// View returns the board seen by the player, in the same format as GoGame.String.
+func (p *Phantom) View(black bool) string {
+	own := WHITE
+	if black {
+		own = BLACK
+	}
+	var sb strings.Builder
+	for x := range p.Game.board.field {
+		for y := range p.Game.board.field[x] {
+			pt := p.Game.board.field[x][y]
+			if pt.State == own || (pt.State != FREE && p.revealed[black][Coord{x, y}]) {
+				sb.WriteString(pt.String())
+			} else {
+				sb.WriteString("*")
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,43 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhantom(t *testing.T) {
+	assert := assert.New(t)
+	g, _ := NewGame(3)
+	p := NewPhantom(g)
+	_, err := p.Play(0, 1, true)
+	assert.NoError(err)
+	assert.Equal("*B*******", p.View(true))
+	assert.Equal("*********", p.View(false))
+	_, err = p.Play(0, 1, false) //occupied, revealed to white
+	assert.Error(err)
+	assert.Equal("*B*******", p.View(false))
+	_, err = p.Play(0, 0, false)
+	assert.NoError(err)
+	assert.Equal("*B*******", p.View(true))
+	assert.Equal("WB*******", p.View(false))
+	_, err = p.Play(0, 0, true) //occupied, revealed to black
+	assert.Error(err)
+	assert.Equal("WB*******", p.View(true))
+
+	captured, err := p.Play(1, 0, true)
+	assert.NoError(err)
+	assert.Equal(1, captured)
+	assert.Equal("*B*B*****", p.View(true))
+	assert.Equal("*B*******", p.View(false))
+	_, err = p.Play(2, 2, false)
+	assert.NoError(err)
+	captured, err = p.Play(0, 0, true)
+	assert.NoError(err)
+	assert.Equal(0, captured)
+	assert.Equal("BB*B*****", p.View(true))
+	assert.Equal("*B******W", p.View(false))
+	_, err = p.Play(2, 2, true) //wrong turn, nothing revealed
+	assert.Error(err)
+	assert.Equal("BB*B*****", p.View(true))
+}
@@ -1,6 +1,9 @@
 package game
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+)
 
 type pointStateType int
 
@@ -8,14 +11,68 @@ const (
 	FREE pointStateType = iota
 	BLACK
 	WHITE
+	RED    //only in multi-color Go
+	GREEN  //only in multi-color Go
+	YELLOW //only in multi-color Go
+)
+
+const (
+	maxColors    = 5
+	stateLetters = "*BWRGY" //letter of each state in the string representation of the board
 )
 
+var colorNames = []string{"black", "white", "red", "green", "yellow"}
+
+// ColorName returns the name of the color of the player, from 0 (black) and 1 (white) to the colors of multi-color Go.
+func ColorName(player int) string {
+	if player < 0 || player >= maxColors {
+		return fmt.Sprintf("player %v", player)
+	}
+	return colorNames[player]
+}
+
+// stateOf returns the state of the points taken by the player, counted from 0 (black).
+func stateOf(player int) pointStateType {
+	return pointStateType(player + 1)
+}
+
+// Coord is the position of a point in the board. X is the row and Y the column.
+type Coord struct {
+	X, Y int
+}
+
 type Point struct {
 	X, Y       int
 	State      pointStateType
 	neighbords []*Point
 	board      *board
-	chainId    int
+	parent     *Point //parent in the union-find tree of the chain, itself in the root. nil if free
+	next       *Point //next stone of the chain, in joining order
+	owner      *chain //only in the root of the tree
+}
+
+// chain returns the chain of the stone, or nil if the point is free.
+func (p *Point) chain() *chain {
+	if p.parent == nil {
+		return nil
+	}
+	for p.parent != p {
+		p = p.parent
+	}
+	return p.owner
+}
+
+// chainId returns the id of the chain of the stone, or 0 if the point is free.
+func (p *Point) chainId() int {
+	if c := p.chain(); c != nil {
+		return c.id
+	}
+	return 0
+}
+
+// unlink takes the point out of its chain, without changing its state.
+func (p *Point) unlink() {
+	p.parent, p.next, p.owner = nil, nil, nil
 }
 
 func (p *Point) Init(b *board, x, y int) {
@@ -25,83 +82,14 @@ func (p *Point) Init(b *board, x, y int) {
 }
 
 func (p Point) String() string {
-	switch p.State {
-	case BLACK:
-		return "B"
-	case WHITE:
-		return "W"
-	default: //FREE
-		return "*"
-	}
+	return string(stateLetters[p.State])
 }
 
 func (p *Point) linkNeighbords() {
-	if p.X == 0 && p.Y == 0 { //top left
-		p.neighbords = []*Point{
-			&(p.board).field[1][0], //bottom
-			&(p.board).field[0][1], //right
-		}
-		return
-	}
-	if p.X == p.board.size-1 && p.Y == 0 { //bottom left
-		p.neighbords = []*Point{
-			&(p.board).field[p.board.size-2][0], //top
-			&(p.board).field[p.board.size-1][1], //right
-		}
-		return
-	}
-	if p.X == 0 && p.Y == p.board.size-1 { //top right
-		p.neighbords = []*Point{
-			&(p.board).field[0][p.board.size-2], //left
-			&(p.board).field[1][p.board.size-1], //bottom
-		}
-		return
-	}
-	if p.X == p.board.size-1 && p.Y == p.board.size-1 { //bottom right
-		p.neighbords = []*Point{
-			&(p.board).field[p.board.size-2][p.board.size-1], //top
-			&(p.board).field[p.board.size-1][p.board.size-2], //left
-		}
-		return
-	}
-	if p.X == 0 { //top border
-		p.neighbords = []*Point{
-			&(p.board).field[0][p.Y+1], //right
-			&(p.board).field[0][p.Y-1], //left
-			&(p.board).field[1][p.Y],   //bottom
-		}
-		return
-	}
-	if p.X == p.board.size-1 { //bottom border
-		p.neighbords = []*Point{
-			&(p.board).field[p.board.size-1][p.Y+1], //right
-			&(p.board).field[p.board.size-1][p.Y-1], //left
-			&(p.board).field[p.board.size-2][p.Y],   //top
-		}
-		return
-	}
-	if p.Y == 0 { //left border
-		p.neighbords = []*Point{
-			&(p.board).field[p.X+1][0], //bottom
-			&(p.board).field[p.X-1][0], //top
-			&(p.board).field[p.X][1],   //right
-		}
-		return
-	}
-	if p.Y == p.board.size-1 { //right border
-		p.neighbords = []*Point{
-			&(p.board).field[p.X+1][p.board.size-1], //bottom
-			&(p.board).field[p.X-1][p.board.size-1], //top
-			&(p.board).field[p.X][p.board.size-2],   //left
-		}
-		return
-	}
-	//not in border or corners, so it has 4 neighbors
-	p.neighbords = []*Point{
-		&(p.board).field[p.X][p.Y+1], //right
-		&(p.board).field[p.X][p.Y-1], //left
-		&(p.board).field[p.X-1][p.Y], //top
-		&(p.board).field[p.X+1][p.Y], //bottom
+	nbrs := neighborsOf(p.board.topology, Coord{p.X, p.Y}, p.board.size)
+	p.neighbords = make([]*Point, len(nbrs))
+	for i, n := range nbrs {
+		p.neighbords[i] = &p.board.field[n.X][n.Y]
 	}
 }
 
@@ -116,85 +104,65 @@ func (p *Point) noSameNeighbor() bool {
 }
 
 func (p *Point) checkNeighbors() error {
+	//p is not a liberty anymore of the chains around it
+	for _, n := range p.neighbords {
+		if c := n.chain(); c != nil {
+			c.removeLiberty(p)
+		}
+	}
 	if p.noSameNeighbor() {
-		chId := len(p.board.chains) + 1
-		p.chainId = chId
+		chId := p.board.newChainId()
 		c, err := NewChain(chId, p)
 		if err != nil {
-			p.chainId = 0
 			return fmt.Errorf("error creating chain: %v", err)
 		}
 		p.board.chains[chId] = c
-		otherPlayerChains := make(map[int]bool)
-		for _, n := range p.neighbords {
-			if _, seen := otherPlayerChains[n.chainId]; !seen && n.State != FREE && n.State != p.State {
-				p.board.chains[n.chainId].liberties -= 1
-				otherPlayerChains[n.chainId] = true
-			}
-		}
-	} else {
-		playerChains := make(map[int]bool)
-		minChainId := p.board.size*p.board.size + 1
-		for _, n := range p.neighbords {
-			if n.State != FREE {
-				_, seen := playerChains[n.chainId]
-				if seen {
-					continue
-				}
-				p.board.chains[n.chainId].liberties -= 1
-				if n.State == p.State {
-					//pick the chain with the min id to join in and merge the other chains of the same player
-					minChainId = min(minChainId, n.chainId)
-					playerChains[n.chainId] = true
-				} else {
-					playerChains[n.chainId] = false
-				}
+		return nil
+	}
+	//join the oldest chain of the player (min id) and merge the other chains of the player into it
+	playerChains := make([]*chain, 0, len(p.neighbords))
+	var oldest *chain
+	for _, n := range p.neighbords {
+		if c := n.chain(); n.State == p.State && !slices.Contains(playerChains, c) {
+			playerChains = append(playerChains, c)
+			if oldest == nil || c.id < oldest.id {
+				oldest = c
 			}
 		}
-		p.chainId = minChainId
-		p.board.chains[p.chainId].add(p)
-		for c := range playerChains {
-			if c != p.chainId && playerChains[c] {
-				p.board.chains[p.chainId].merge(p.board.chains[c])
-			}
+	}
+	oldest.add(p)
+	for _, c := range playerChains {
+		if c != oldest {
+			oldest.merge(c)
 		}
 	}
 	return nil
 }
 
-func (p *Point) updateNeighborsLiberties(black bool) {
-	seenChainIds := make(map[int]bool)
+// updateNeighborsLiberties adds the point as a liberty of every chain around it, after it was freed.
+func (p *Point) updateNeighborsLiberties() {
 	for _, n := range p.neighbords {
-		if seenChainIds[n.chainId] {
-			continue
-		}
-		if n.board.chains[n.chainId].isBlack != black {
-			n.board.chains[n.chainId].liberties += 1
-			seenChainIds[n.chainId] = true
+		if n.State != FREE {
+			n.chain().addLiberty(p)
 		}
 	}
 }
 
-func (p *Point) play(black bool) error {
+func (p *Point) play(state pointStateType) error {
 	switch p.State {
-	case BLACK:
-		return fmt.Errorf("point already taken by black")
-	case WHITE:
-		return fmt.Errorf("point already taken by white")
-	default: //FREE
-		if black {
-			p.State = BLACK
-		} else {
-			p.State = WHITE
-		}
+	case FREE:
+		p.State = state
 		err := p.checkNeighbors()
 		if err != nil {
 			return fmt.Errorf("error during neighbors checking: %v", err)
 		}
 		return nil
+	default:
+		return fmt.Errorf("point already taken by %s", ColorName(int(p.State)-1))
 	}
 }
 
 func (p *Point) free() {
 	p.State = FREE
+	p.unlink()
 }
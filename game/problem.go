@@ -0,0 +1,199 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+const defaultSGFSize = 19
+
+// maxProblemSize is the biggest board of a problem.
+const maxProblemSize = 25
+
+type ProblemResult int
+
+const (
+	ProblemOpen    ProblemResult = iota // The solution goes on, the player must keep playing.
+	ProblemCorrect                      // The player reached the end of a correct variation.
+	ProblemWrong                        // The player reached a wrong variation.
+	ProblemOffTree                      // The move is not in the solution tree. It is not played.
+)
+
+func (r ProblemResult) String() string {
+	switch r {
+	case ProblemCorrect:
+		return "correct"
+	case ProblemWrong:
+		return "wrong"
+	case ProblemOffTree:
+		return "off-tree"
+	default:
+		return "open"
+	}
+}
+
+// Problem is a life-and-death problem (tsumego) loaded from SGF: the setup stones of the root node and
+// a solution tree, where the player moves alternate with the responses of the problem.
+//
+// Variations are marked as wrong with "wrong" or "incorrect" in their comment or a WV or BM property,
+// and as correct with "correct" or "right" in their comment or a TE property. A variation ending
+// without a correct mark is wrong.
+type Problem struct {
+	Game   *GoGame
+	Black  bool // Side of the player solving the problem.
+	node   *SGFNode
+	result ProblemResult
+}
+
+// NewProblem loads the first game tree of the SGF, in a board up to 25 x 25. The side to play is the PL
+// property, or the side of the first move of the solution.
+func NewProblem(sgf string) (*Problem, error) {
+	roots, err := ParseSGF(sgf)
+	if err != nil {
+		return nil, err
+	}
+	root := roots[0]
+	size, err := sgfSize(root, maxProblemSize)
+	if err != nil {
+		return nil, err
+	}
+	g, err := NewGame(size)
+	if err != nil {
+		return nil, err
+	}
+	for _, black := range []bool{true, false} {
+		id := "AW"
+		if black {
+			id = "AB"
+		}
+		for _, v := range root.Props[id] {
+			points, err := sgfPointList(v, size)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range points {
+				if err = g.Setup(c.X, c.Y, black); err != nil {
+					return nil, fmt.Errorf("invalid setup stone %q: %v", v, err)
+				}
+			}
+		}
+	}
+	if len(root.Children) == 0 {
+		return nil, fmt.Errorf("problem without solution")
+	}
+	p := &Problem{Game: g, Black: true, node: root}
+	switch root.Prop("PL") {
+	case "B":
+		p.Black = true
+	case "W":
+		p.Black = false
+	default:
+		if _, black, ok, _ := root.Children[0].Move(size); ok {
+			p.Black = black
+		}
+	}
+	g.BlackPlayedLast = !p.Black
+	return p, nil
+}
+
+// Play checks the player move against the solution tree. If the move is in the tree, it is played
+// and the first response of the tree is played automatically and returned. Moves not in the tree are
+// not played, so the player can try again.
+func (p *Problem) Play(x, y int) (ProblemResult, *Move, error) {
+	if p.result == ProblemCorrect || p.result == ProblemWrong {
+		return p.result, nil, fmt.Errorf("problem already finished")
+	}
+	size := p.Game.Size()
+	var next *SGFNode
+	for _, c := range p.node.Children {
+		if m, black, ok, _ := c.Move(size); ok && black == p.Black && m == (Move{X: x, Y: y}) {
+			next = c
+			break
+		}
+	}
+	if next == nil {
+		if err := p.Game.Clone().Play(x, y, p.Black); err != nil {
+			return ProblemOpen, nil, err
+		}
+		return ProblemOffTree, nil, nil
+	}
+	if err := p.Game.Play(x, y, p.Black); err != nil {
+		return ProblemOpen, nil, err
+	}
+	p.node = next
+	correct, wrong := isCorrect(next), isWrong(next)
+	var reply *Move
+	if len(next.Children) > 0 {
+		r := next.Children[0]
+		m, black, ok, err := r.Move(size)
+		if err != nil {
+			return ProblemOpen, nil, fmt.Errorf("invalid response in the solution tree: %v", err)
+		}
+		if !ok || black == p.Black {
+			return ProblemOpen, nil, fmt.Errorf("invalid response in the solution tree: no move of the opponent")
+		}
+		if m.Pass {
+			err = p.Game.Pass(!p.Black)
+		} else {
+			err = p.Game.Play(m.X, m.Y, !p.Black)
+		}
+		if err != nil {
+			return ProblemOpen, nil, fmt.Errorf("invalid response in the solution tree: %v", err)
+		}
+		p.node = r
+		reply = &m
+		correct, wrong = correct || isCorrect(r), wrong || isWrong(r)
+	}
+	switch {
+	case wrong:
+		p.result = ProblemWrong
+	case len(p.node.Children) > 0:
+		p.result = ProblemOpen
+	case correct:
+		p.result = ProblemCorrect
+	default:
+		p.result = ProblemWrong
+	}
+	return p.result, reply, nil
+}
+
+// Comment returns the comment of the last node reached in the solution tree.
+func (p *Problem) Comment() string {
+	return p.node.Prop("C")
+}
+
+func isCorrect(n *SGFNode) bool {
+	c := strings.ToLower(n.Prop("C"))
+	_, te := n.Props["TE"]
+	return !isWrong(n) && (te || strings.Contains(c, "correct") || strings.Contains(c, "right"))
+}
+
+func isWrong(n *SGFNode) bool {
+	c := strings.ToLower(n.Prop("C"))
+	_, wv := n.Props["WV"]
+	_, bm := n.Props["BM"]
+	return wv || bm || strings.Contains(c, "wrong") || strings.Contains(c, "incorrect")
+}
+
+// sgfPointList parses a point, or a compressed rectangle of points like "aa:cc".
+func sgfPointList(v string, size int) ([]Coord, error) {
+	from, to, rect := strings.Cut(v, ":")
+	a, err := ParseSGFCoord(from, size)
+	if err != nil {
+		return nil, err
+	}
+	if !rect {
+		return []Coord{a}, nil
+	}
+	b, err := ParseSGFCoord(to, size)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]Coord, 0)
+	for x := min(a.X, b.X); x <= max(a.X, b.X); x++ {
+		for y := min(a.Y, b.Y); y <= max(a.Y, b.Y); y++ {
+			points = append(points, Coord{x, y})
+		}
+	}
+	return points, nil
+}
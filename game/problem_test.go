@@ -0,0 +1,69 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testProblem = `(;SZ[5]AW[aa][ba]AB[ab]PL[B]
+	(;B[bb];W[ca];B[da]C[Correct!])
+	(;B[ca];W[bb]C[White escapes]))`
+
+func TestProblem(t *testing.T) {
+	assert := assert.New(t)
+	p, err := NewProblem(testProblem)
+	assert.NoError(err)
+	assert.True(p.Black)
+	assert.Equal("WW***B*******************", p.Game.String())
+
+	_, _, err = p.Play(0, 0) //occupied
+	assert.Error(err)
+	r, reply, err := p.Play(4, 4)
+	assert.NoError(err)
+	assert.Equal(ProblemOffTree, r)
+	assert.Nil(reply)
+	assert.Equal("WW***B*******************", p.Game.String())
+
+	r, reply, err = p.Play(1, 1)
+	assert.NoError(err)
+	assert.Equal(ProblemOpen, r)
+	assert.Equal(&Move{X: 0, Y: 2}, reply)
+	r, reply, err = p.Play(0, 3)
+	assert.NoError(err)
+	assert.Equal(ProblemCorrect, r)
+	assert.Nil(reply)
+	assert.Equal("Correct!", p.Comment())
+	_, _, err = p.Play(1, 2)
+	assert.Error(err)
+
+	p, _ = NewProblem(testProblem)
+	r, reply, err = p.Play(0, 2)
+	assert.NoError(err)
+	assert.Equal(ProblemWrong, r)
+	assert.Equal(&Move{X: 1, Y: 1}, reply)
+	assert.Equal("wrong", r.String())
+}
+
+func TestProblemSide(t *testing.T) {
+	assert := assert.New(t)
+	p, err := NewProblem("(;SZ[5]AB[aa:bb](;W[ca]C[RIGHT]))")
+	assert.NoError(err)
+	assert.False(p.Black)
+	assert.True(p.Game.BlackPlayedLast)
+	r, _, err := p.Play(0, 2)
+	assert.NoError(err)
+	assert.Equal(ProblemCorrect, r)
+
+	_, err = NewProblem("(;SZ[5]AB[aa])")
+	assert.Error(err)
+	_, err = NewProblem("(;SZ[60000];B[aa])")
+	assert.ErrorContains(err, "invalid board size")
+	_, err = NewProblem("(;SZ[26];B[aa])")
+	assert.ErrorContains(err, "invalid board size")
+	p, err = NewProblem("(;SZ[25];B[aa])")
+	assert.NoError(err)
+	assert.Equal(25, p.Game.Size())
+	_, err = NewProblem("(;SZ[5]AB[zz];B[aa])")
+	assert.Error(err)
+}
@@ -0,0 +1,139 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ReadResult is the outcome of the tactical reading of a group.
+type ReadResult struct {
+	Captured bool   // true if the group is captured whatever the defender does.
+	PV       []Move // Principal variation, alternating players and starting with the player in turn.
+}
+
+// ReadCapture reads the forced sequences around the group in (x, y), that must have one or two liberties,
+// to decide whether it can be captured or it escapes. The player in turn moves first.
+//
+// The attacker only plays on the liberties of the group, and the defender extends on them or captures
+// attacking stones in atari, like in ladders. A group reaching three liberties escapes, so ladder breakers
+// are found by the reading itself. Sequences longer than maxDepth moves are considered escapes.
+// The reading is done on clones of the game, which is never modified.
+func (g *GoGame) ReadCapture(x, y int, maxDepth int) (ReadResult, error) {
+	if x < 0 || x > (g.board.size-1) || y < 0 || y > (g.board.size-1) {
+		return ReadResult{}, fmt.Errorf("invalid position (%v, %v)", x, y)
+	}
+	p := &g.board.field[x][y]
+	if p.State == FREE {
+		return ReadResult{}, fmt.Errorf("no group in (%v, %v)", x, y)
+	}
+	if l := g.board.chains[p.chainId()].liberties; l > 2 {
+		return ReadResult{}, fmt.Errorf("group in (%v, %v) has %v liberties, only groups with one or two can be read", x, y, l)
+	}
+	attackerTurn := (p.State == BLACK) == g.BlackPlayedLast
+	var captured bool
+	var pv []Move
+	if attackerTurn {
+		captured, pv = attack(g, Coord{x, y}, maxDepth)
+	} else {
+		var escaped bool
+		escaped, pv = defend(g, Coord{x, y}, maxDepth)
+		captured = !escaped
+	}
+	return ReadResult{Captured: captured, PV: pv}, nil
+}
+
+// attack reports if the attacker, in turn, captures the group in target, with the sequence proving it.
+func attack(g *GoGame, target Coord, depth int) (bool, []Move) {
+	libs := targetLiberties(g, target)
+	if len(libs) == 0 {
+		return true, []Move{}
+	}
+	if len(libs) > 2 || depth <= 0 {
+		return false, []Move{}
+	}
+	attacker := g.board.field[target.X][target.Y].State != BLACK
+	var bestPV []Move
+	for _, l := range libs {
+		c := g.Clone()
+		if c.Play(l.X, l.Y, attacker) != nil {
+			continue
+		}
+		if c.board.field[target.X][target.Y].State == FREE {
+			return true, []Move{{X: l.X, Y: l.Y}}
+		}
+		escaped, pv := defend(c, target, depth-1)
+		if !escaped {
+			return true, append([]Move{{X: l.X, Y: l.Y}}, pv...)
+		}
+		if bestPV == nil || len(pv)+1 > len(bestPV) {
+			bestPV = append([]Move{{X: l.X, Y: l.Y}}, pv...)
+		}
+	}
+	if bestPV == nil {
+		bestPV = []Move{}
+	}
+	return false, bestPV
+}
+
+// defend reports if the defender, in turn, saves the group in target, with the sequence proving it.
+func defend(g *GoGame, target Coord, depth int) (bool, []Move) {
+	libs := targetLiberties(g, target)
+	if len(libs) > 2 || depth <= 0 {
+		return true, []Move{}
+	}
+	defender := g.board.field[target.X][target.Y].State == BLACK
+	var bestPV []Move
+	for _, m := range append(counterCaptures(g, target), libs...) {
+		c := g.Clone()
+		if c.Play(m.X, m.Y, defender) != nil {
+			continue
+		}
+		captured, pv := attack(c, target, depth-1)
+		if !captured {
+			return true, append([]Move{{X: m.X, Y: m.Y}}, pv...)
+		}
+		if bestPV == nil || len(pv)+1 > len(bestPV) {
+			bestPV = append([]Move{{X: m.X, Y: m.Y}}, pv...)
+		}
+	}
+	if bestPV == nil {
+		bestPV = []Move{}
+	}
+	return false, bestPV
+}
+
+// targetLiberties returns the liberties of the group in target, sorted. None if it was captured.
+func targetLiberties(g *GoGame, target Coord) []Coord {
+	p := &g.board.field[target.X][target.Y]
+	if p.State == FREE {
+		return []Coord{}
+	}
+	libs := make([]*Point, 0)
+	for l := range g.board.chains[p.chainId()].libs {
+		libs = append(libs, l)
+	}
+	return coordsOf(libs)
+}
+
+// counterCaptures returns the moves capturing attacker chains in atari next to the group in target.
+func counterCaptures(g *GoGame, target Coord) []Coord {
+	c := g.board.field[target.X][target.Y].chain()
+	seen := make(map[int]bool)
+	moves := make([]*Point, 0)
+	for _, p := range c.stones() {
+		for _, n := range p.neighbords {
+			if n.State == FREE || n.State == p.State || seen[n.chainId()] {
+				continue
+			}
+			seen[n.chainId()] = true
+			if enemy := g.board.chains[n.chainId()]; enemy.liberties == 1 {
+				for l := range enemy.libs {
+					if !slices.Contains(moves, l) {
+						moves = append(moves, l)
+					}
+				}
+			}
+		}
+	}
+	return coordsOf(moves)
+}
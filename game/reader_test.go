@@ -0,0 +1,82 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ladderGame returns a game with a black stone in (4, 4) that white can chase in a ladder,
+// with black stones added in the given rows of the breakers.
+func ladderGame(t *testing.T, row1, row7 string) *GoGame {
+	g := newGameFromRows(t,
+		"*********",
+		row1,
+		"*********",
+		"****W****",
+		"***WB****",
+		"*****W***",
+		"*********",
+		row7,
+		"*********",
+	)
+	if !g.BlackPlayedLast {
+		g.Pass(true)
+	}
+	return g
+}
+
+func TestReadCaptureLadder(t *testing.T) {
+	assert := assert.New(t)
+	g := ladderGame(t, "*********", "*********")
+	before := g.String()
+	r, err := g.ReadCapture(4, 4, 100)
+	assert.NoError(err)
+	assert.True(r.Captured)
+	assert.Equal([]Move{{X: 4, Y: 5}, {X: 5, Y: 4}, {X: 6, Y: 4}, {X: 5, Y: 3}, {X: 5, Y: 2}, {X: 6, Y: 3}}, r.PV[:6])
+	assert.Equal(before, g.String())
+	//the pv captures the black stones
+	c := g.Clone()
+	black := false
+	for _, m := range r.PV {
+		assert.NoError(c.Play(m.X, m.Y, black))
+		black = !black
+	}
+	assert.Equal(FREE, c.board.field[4][4].State)
+}
+
+func TestReadCaptureLadderBreakers(t *testing.T) {
+	assert := assert.New(t)
+	//one breaker, white ladders in the other direction
+	g := ladderGame(t, "*********", "**B******")
+	r, err := g.ReadCapture(4, 4, 100)
+	assert.NoError(err)
+	assert.True(r.Captured)
+	assert.Equal(Move{X: 5, Y: 4}, r.PV[0])
+	//breakers in both directions
+	g = ladderGame(t, "******B**", "**B******")
+	r, err = g.ReadCapture(4, 4, 100)
+	assert.NoError(err)
+	assert.False(r.Captured)
+	//short reading is not enough to capture
+	g = ladderGame(t, "*********", "*********")
+	r, err = g.ReadCapture(4, 4, 4)
+	assert.NoError(err)
+	assert.False(r.Captured)
+}
+
+func TestReadCaptureDefenderFirst(t *testing.T) {
+	assert := assert.New(t)
+	g := ladderGame(t, "*********", "*********")
+	g.Play(4, 5, false)
+	r, err := g.ReadCapture(4, 4, 100)
+	assert.NoError(err)
+	assert.True(r.Captured)
+	assert.Equal(Move{X: 5, Y: 4}, r.PV[0])
+	_, err = g.ReadCapture(3, 4, 100)
+	assert.Error(err) //too many liberties
+	_, err = g.ReadCapture(2, 2, 100)
+	assert.Error(err)
+	_, err = g.ReadCapture(9, 2, 100)
+	assert.Error(err)
+}
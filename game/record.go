@@ -0,0 +1,281 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Record is a game record: the players, the rules, the setup stones and the moves of the main line.
+type Record struct {
+	Size        int
+	Komi        float64
+	Rules       string // Name of the rules, like "Japanese" or "Chinese".
+	BlackPlayer string
+	WhitePlayer string
+	BlackRank   string
+	WhiteRank   string
+	Result      string  // In SGF notation, like "B+3.5", "W+R" or "0".
+	BlackSetup  []Coord // Stones placed before the first move, like handicap stones.
+	WhiteSetup  []Coord
+	Moves       []RecordMove
+}
+
+// RecordMove is a move of a game record.
+type RecordMove struct {
+	Move
+	Black bool
+}
+
+// RecordFromSGF reads the record of the game tree with the given root. Only the main line is read.
+func RecordFromSGF(root *SGFNode) (*Record, error) {
+	r := &Record{}
+	var err error
+	if r.Size, err = sgfSize(root, maxSGFSize); err != nil {
+		return nil, err
+	}
+	if km := root.Prop("KM"); km != "" {
+		if r.Komi, err = strconv.ParseFloat(strings.TrimSpace(km), 64); err != nil {
+			return nil, fmt.Errorf("invalid komi %q", km)
+		}
+	}
+	r.Rules = root.Prop("RU")
+	r.BlackPlayer, r.WhitePlayer = root.Prop("PB"), root.Prop("PW")
+	r.BlackRank, r.WhiteRank = root.Prop("BR"), root.Prop("WR")
+	r.Result = root.Prop("RE")
+	for n := root; n != nil; {
+		for _, id := range []string{"AB", "AW"} {
+			if len(n.Props[id]) > 0 && n != root {
+				return nil, fmt.Errorf("setup stones are only supported in the root node")
+			}
+			for _, v := range n.Props[id] {
+				points, err := sgfPointList(v, r.Size)
+				if err != nil {
+					return nil, err
+				}
+				if id == "AB" {
+					r.BlackSetup = append(r.BlackSetup, points...)
+				} else {
+					r.WhiteSetup = append(r.WhiteSetup, points...)
+				}
+			}
+		}
+		m, black, ok, err := n.Move(r.Size)
+		if err != nil {
+			return nil, fmt.Errorf("move %v: %v", len(r.Moves)+1, err)
+		}
+		if ok {
+			r.Moves = append(r.Moves, RecordMove{Move: m, Black: black})
+		}
+		if len(n.Children) == 0 {
+			break
+		}
+		n = n.Children[0]
+	}
+	return r, nil
+}
+
+// Replay plays the record in a new game, calling fn after the setup and after every move with the number of
+// moves played. If a player moves twice in a row, the other one is considered to pass. It stops at the first
+// illegal move, or at the first error returned by fn.
+func (r *Record) Replay(fn func(moves int, g *GoGame) error) error {
+	g, err := NewGame(r.Size)
+	if err != nil {
+		return err
+	}
+	for _, black := range []bool{true, false} {
+		setup := r.WhiteSetup
+		if black {
+			setup = r.BlackSetup
+		}
+		for _, c := range setup {
+			if err = g.Setup(c.X, c.Y, black); err != nil {
+				return fmt.Errorf("invalid setup stone (%v, %v): %v", c.X, c.Y, err)
+			}
+		}
+	}
+	if len(r.BlackSetup) > 0 && len(r.Moves) > 0 && !r.Moves[0].Black {
+		g.BlackPlayedLast = true //handicap games, white moves first
+	}
+	if fn != nil {
+		if err = fn(0, g); err != nil {
+			return err
+		}
+	}
+	for i, m := range r.Moves {
+		if g.BlackPlayedLast == m.Black {
+			g.Pass(!m.Black)
+		}
+		if m.Pass {
+			err = g.Pass(m.Black)
+		} else {
+			err = g.Play(m.X, m.Y, m.Black)
+		}
+		if err != nil {
+			return fmt.Errorf("illegal move %v (%v, %v): %v", i+1, m.X, m.Y, err)
+		}
+		if fn != nil {
+			if err = fn(i+1, g); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Game returns the game at the end of the record.
+func (r *Record) Game() (*GoGame, error) {
+	var last *GoGame
+	err := r.Replay(func(moves int, g *GoGame) error {
+		last = g
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return last, nil
+}
+
+// SGF writes the record as an SGF game, which RecordFromSGF reads back to the same record.
+func (r *Record) SGF() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "(;GM[1]FF[4]SZ[%v]", r.Size)
+	if r.Komi != 0 {
+		fmt.Fprintf(&sb, "KM[%s]", strconv.FormatFloat(r.Komi, 'f', -1, 64))
+	}
+	for _, p := range []struct{ id, v string }{
+		{"RU", r.Rules}, {"PB", r.BlackPlayer}, {"PW", r.WhitePlayer},
+		{"BR", r.BlackRank}, {"WR", r.WhiteRank}, {"RE", r.Result},
+	} {
+		if p.v != "" {
+			fmt.Fprintf(&sb, "%s[%s]", p.id, sgfEscape(p.v))
+		}
+	}
+	for _, setup := range []struct {
+		id     string
+		points []Coord
+	}{{"AB", r.BlackSetup}, {"AW", r.WhiteSetup}} {
+		if len(setup.points) > 0 {
+			sb.WriteString(setup.id)
+			for _, c := range setup.points {
+				sb.WriteString("[" + SGFPoint(c) + "]")
+			}
+		}
+	}
+	for i, m := range r.Moves {
+		if i%10 == 0 {
+			sb.WriteByte('\n')
+		}
+		color := "W"
+		if m.Black {
+			color = "B"
+		}
+		point := ""
+		if !m.Pass {
+			point = SGFPoint(Coord{m.X, m.Y})
+		}
+		fmt.Fprintf(&sb, ";%s[%s]", color, point)
+	}
+	sb.WriteString(")\n")
+	return sb.String()
+}
+
+// RecordFromGame returns the record of the setup stones and the history of a game of black and white on the
+// square grid. Replaying it gives the same position.
+func RecordFromGame(g *GoGame) (*Record, error) {
+	if g.Colors > 0 {
+		return nil, fmt.Errorf("multi-color games can not be recorded")
+	}
+	if _, ok := g.board.topology.(Grid); !ok {
+		return nil, fmt.Errorf("only games on the square grid can be recorded")
+	}
+	r := &Record{Size: g.board.size, Moves: g.History()}
+	for _, s := range g.setup {
+		if s.Black {
+			r.BlackSetup = append(r.BlackSetup, Coord{s.X, s.Y})
+		} else {
+			r.WhiteSetup = append(r.WhiteSetup, Coord{s.X, s.Y})
+		}
+	}
+	return r, nil
+}
+
+func sgfEscape(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `]`, `\]`).Replace(v)
+}
+
+// HandicapStones returns the usual places of n handicap stones, from 2 to 9, on the star points: first the
+// opposite corners, then the other corners, the sides and the center. Boards must be odd and at least 7x7,
+// and more than 4 stones need a board of at least 9x9.
+func HandicapStones(size, n int) ([]Coord, error) {
+	if n < 2 || n > 9 || size < 7 || size%2 == 0 || (n > 4 && size < 9) {
+		return nil, fmt.Errorf("invalid handicap of %v stones in a %v x %v board", n, size, size)
+	}
+	edge := 3
+	if size < 13 {
+		edge = 2
+	}
+	far, mid := size-1-edge, size/2
+	stones := []Coord{{edge, far}, {far, edge}, {far, far}, {edge, edge}}
+	if n >= 6 {
+		stones = append(stones, Coord{mid, edge}, Coord{mid, far})
+	}
+	if n >= 8 {
+		stones = append(stones, Coord{edge, mid}, Coord{far, mid})
+	}
+	stones = stones[:min(n, len(stones))]
+	if n%2 == 1 && n >= 5 {
+		stones = append(stones, Coord{mid, mid})
+	}
+	return stones, nil
+}
+
+// setHandicap places the handicap stones of the record in their usual places, if it has no setup stones.
+func (r *Record) setHandicap(n int) error {
+	if n < 2 || len(r.BlackSetup) > 0 {
+		return nil
+	}
+	stones, err := HandicapStones(r.Size, n)
+	if err != nil {
+		return err
+	}
+	r.BlackSetup = stones
+	return nil
+}
+
+// checkSize rejects the boards that can not be recorded in SGF, before a game is created for them.
+func (r *Record) checkSize() error {
+	if r.Size < 2 || r.Size > maxSGFSize {
+		return fmt.Errorf("invalid board size %v, must be between 2 and %v", r.Size, maxSGFSize)
+	}
+	return nil
+}
+
+// checked replays the record, returning the first illegal move as an error. The record is returned even
+// then, so the callers can still look at it or report the error like in records of other formats.
+func (r *Record) checked() (*Record, error) {
+	return r, r.Replay(nil)
+}
+
+// scoreResult returns the result of a game won by points in SGF notation.
+func scoreResult(black bool, score float64) string {
+	winner := "W"
+	if black {
+		winner = "B"
+	}
+	return winner + "+" + strconv.FormatFloat(score, 'f', -1, 64)
+}
+
+// splitPlayer separates the name and the rank of a player written like "name 3d", or "name (3d)".
+func splitPlayer(s string) (name, rank string) {
+	s = strings.TrimSpace(s)
+	i := strings.LastIndexAny(s, " \t")
+	if i < 0 {
+		return s, ""
+	}
+	rank = strings.Trim(s[i+1:], "()*")
+	if rank == "" || rank[0] < '0' || rank[0] > '9' {
+		return s, ""
+	}
+	return strings.TrimSpace(s[:i]), rank
+}
@@ -0,0 +1,70 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordFromSGF(t *testing.T) {
+	assert := assert.New(t)
+	roots, err := ParseSGF(`(;SZ[5]KM[0.5]PB[Ana]PW[Bruno]BR[3k]WR[2k]RE[W+R]AB[aa][bb]
+		;W[cc];B[dd];B[]
+		(;W[ee])(;W[ab]))`)
+	assert.NoError(err)
+	r, err := RecordFromSGF(roots[0])
+	assert.NoError(err)
+	assert.Equal(5, r.Size)
+	assert.Equal(0.5, r.Komi)
+	assert.Equal("Ana", r.BlackPlayer)
+	assert.Equal("2k", r.WhiteRank)
+	assert.Equal("W+R", r.Result)
+	assert.Equal([]Coord{{0, 0}, {1, 1}}, r.BlackSetup)
+	assert.Equal([]RecordMove{
+		{Move: Move{X: 2, Y: 2}},
+		{Move: Move{X: 3, Y: 3}, Black: true},
+		{Move: Move{Pass: true}, Black: true},
+		{Move: Move{X: 4, Y: 4}},
+	}, r.Moves)
+
+	positions := make([]string, 0)
+	err = r.Replay(func(moves int, g *GoGame) error {
+		assert.Equal(len(positions), moves)
+		positions = append(positions, g.String())
+		return nil
+	})
+	assert.NoError(err)
+	assert.Len(positions, 5)
+	assert.Equal("B*****B*****W*****B*****W", positions[4])
+
+	r.Moves = append(r.Moves, RecordMove{Move: Move{X: 4, Y: 4}, Black: true})
+	_, err = r.Game()
+	assert.ErrorContains(err, "illegal move 5")
+
+	roots, _ = ParseSGF("(;SZ[5];B[aa];AW[bb])")
+	_, err = RecordFromSGF(roots[0])
+	assert.Error(err)
+	roots, _ = ParseSGF("(;SZ[60000];B[aa])")
+	_, err = RecordFromSGF(roots[0])
+	assert.ErrorContains(err, "invalid board size")
+}
+
+func TestHandicapStones(t *testing.T) {
+	assert := assert.New(t)
+	stones, err := HandicapStones(19, 2)
+	assert.NoError(err)
+	assert.Equal([]Coord{{3, 15}, {15, 3}}, stones)
+	stones, _ = HandicapStones(19, 5)
+	assert.Equal([]Coord{{3, 15}, {15, 3}, {15, 15}, {3, 3}, {9, 9}}, stones)
+	stones, _ = HandicapStones(19, 8)
+	assert.Equal([]Coord{{3, 15}, {15, 3}, {15, 15}, {3, 3}, {9, 3}, {9, 15}, {3, 9}, {15, 9}}, stones)
+	stones, _ = HandicapStones(19, 9)
+	assert.ElementsMatch(Hoshi(19), stones)
+	stones, _ = HandicapStones(9, 4)
+	assert.Equal([]Coord{{2, 6}, {6, 2}, {6, 6}, {2, 2}}, stones)
+
+	for _, c := range []struct{ size, n int }{{19, 1}, {19, 10}, {8, 2}, {7, 5}} {
+		_, err = HandicapStones(c.size, c.n)
+		assert.Error(err, "%v stones in %v", c.n, c.size)
+	}
+}
@@ -0,0 +1,133 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Score is the final count of a game.
+type Score struct {
+	Black float64
+	White float64 // Komi included.
+}
+
+// String returns the result in SGF notation: "B+3.5", "W+0.5" or "0" for a draw.
+func (s Score) String() string {
+	diff := s.Black - s.White
+	switch {
+	case diff > 0:
+		return "B+" + strconv.FormatFloat(diff, 'f', -1, 64)
+	case diff < 0:
+		return "W+" + strconv.FormatFloat(-diff, 'f', -1, 64)
+	default:
+		return "0"
+	}
+}
+
+// AreaScore counts the game with area scoring (Chinese rules): stones on the board plus surrounded free points.
+// dead lists one stone of each chain agreed as dead, removed before counting.
+// Liberties shared by chains in seki are dame, counted for nobody.
+func (g *GoGame) AreaScore(komi float64, dead []Coord) (Score, error) {
+	b, _, err := g.withoutDead(dead)
+	if err != nil {
+		return Score{}, err
+	}
+	black, white := b.territory(false)
+	s := Score{Black: float64(black), White: float64(white) + komi}
+	for x := range b.field {
+		for y := range b.field[x] {
+			switch b.field[x][y].State {
+			case BLACK:
+				s.Black += 1
+			case WHITE:
+				s.White += 1
+			}
+		}
+	}
+	return s, nil
+}
+
+// TerritoryScore counts the game with territory scoring (Japanese rules): surrounded free points plus
+// prisoners, the stones captured during the game and the dead stones.
+// dead lists one stone of each chain agreed as dead, removed before counting.
+// Liberties shared by chains in seki are dame, and eyes of chains in seki are not territory.
+func (g *GoGame) TerritoryScore(komi float64, dead []Coord) (Score, error) {
+	b, prisoners, err := g.withoutDead(dead)
+	if err != nil {
+		return Score{}, err
+	}
+	black, white := b.territory(true)
+	return Score{
+		Black: float64(black + g.WhiteCaptures + prisoners[WHITE]),
+		White: float64(white+g.BlackCaptures+prisoners[BLACK]) + komi,
+	}, nil
+}
+
+// withoutDead returns a copy of the board without the dead chains, and the number of dead stones of each player.
+func (g *GoGame) withoutDead(dead []Coord) (*board, map[pointStateType]int, error) {
+	b := g.board.clone()
+	prisoners := make(map[pointStateType]int)
+	for _, d := range dead {
+		if d.X < 0 || d.X > (b.size-1) || d.Y < 0 || d.Y > (b.size-1) {
+			return nil, nil, fmt.Errorf("invalid position (%v, %v)", d.X, d.Y)
+		}
+		p := &b.field[d.X][d.Y]
+		if p.State == FREE {
+			continue //already removed with its chain
+		}
+		state := p.State
+		prisoners[state] += b.deleteChain(p.chainId())
+	}
+	return b, prisoners, nil
+}
+
+// territory counts the free points surrounded by each player. Regions touching a seki dame are neutral,
+// and also the eyes of the chains in seki if excludeSekiEyes is true.
+func (b *board) territory(excludeSekiEyes bool) (black int, white int) {
+	neutral := make(map[*Point]bool)
+	for _, s := range b.seki() {
+		for _, d := range s.Dame {
+			neutral[&b.field[d.X][d.Y]] = true
+		}
+		if excludeSekiEyes {
+			for _, c := range s.Chains {
+				for l := range b.chains[b.field[c[0].X][c[0].Y].chainId()].libs {
+					neutral[l] = true
+				}
+			}
+		}
+	}
+	seen := make(map[*Point]bool)
+	for x := range b.field {
+		for y := range b.field[x] {
+			start := &b.field[x][y]
+			if start.State != FREE || seen[start] {
+				continue
+			}
+			region := []*Point{start}
+			seen[start] = true
+			border := make(map[pointStateType]bool)
+			isNeutral := false
+			for i := 0; i < len(region); i++ {
+				isNeutral = isNeutral || neutral[region[i]]
+				for _, n := range region[i].neighbords {
+					if n.State != FREE {
+						border[n.State] = true
+					} else if !seen[n] {
+						seen[n] = true
+						region = append(region, n)
+					}
+				}
+			}
+			if isNeutral || len(border) != 1 {
+				continue
+			}
+			if border[BLACK] {
+				black += len(region)
+			} else {
+				white += len(region)
+			}
+		}
+	}
+	return black, white
+}
@@ -0,0 +1,53 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScore(t *testing.T) {
+	assert := assert.New(t)
+	g := newGameFromRows(t,
+		"*B*W*",
+		"*B*W*",
+		"WB*W*",
+		"*B*W*",
+		"*B*W*",
+	)
+	s, err := g.AreaScore(0.5, nil)
+	assert.NoError(err)
+	assert.Equal(Score{Black: 5, White: 11.5}, s)
+	s, err = g.AreaScore(0.5, []Coord{{2, 0}})
+	assert.NoError(err)
+	assert.Equal(Score{Black: 10, White: 10.5}, s)
+	assert.Equal("W+0.5", s.String())
+	s, err = g.TerritoryScore(6.5, []Coord{{2, 0}, {2, 0}})
+	assert.NoError(err)
+	assert.Equal(Score{Black: 6, White: 11.5}, s)
+	assert.Equal("W+5.5", s.String())
+	assert.Equal("0", Score{Black: 3, White: 3}.String())
+	_, err = g.AreaScore(0, []Coord{{5, 0}})
+	assert.Error(err)
+}
+
+func TestScoreSeki(t *testing.T) {
+	//black and white in seki in the top, each one with an eye and a shared liberty
+	assert := assert.New(t)
+	g := newGameFromRows(t,
+		"*B*W*",
+		"BBBWW",
+		"WWWBB",
+		"*****",
+		"*****",
+	)
+	s, err := g.AreaScore(0, nil)
+	assert.NoError(err)
+	assert.Equal(Score{Black: 7, White: 7}, s) //eyes counted, dame not
+	s, err = g.TerritoryScore(0, nil)
+	assert.NoError(err)
+	assert.Equal(Score{Black: 0, White: 0}, s) //eyes in seki are not territory
+	s, err = g.TerritoryScore(0, []Coord{{2, 0}})
+	assert.NoError(err)
+	assert.Equal(Score{Black: 14 + 3, White: 1}, s) //without the white wall there is no seki
+}
@@ -0,0 +1,210 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxSGFSize is the biggest board of the SGF format, which names the columns and rows with a-z and A-Z.
+const maxSGFSize = 52
+
+// SGFNode is a node of an SGF game tree. Each variation is a child; the main line is always the first one.
+type SGFNode struct {
+	Props    map[string][]string
+	Children []*SGFNode
+	Parent   *SGFNode
+}
+
+// Prop returns the first value of the property, or an empty string if the node does not have it.
+func (n *SGFNode) Prop(id string) string {
+	if v := n.Props[id]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// Move returns the move of the node, if it has a B or W property.
+func (n *SGFNode) Move(size int) (m Move, black bool, ok bool, err error) {
+	v, black := n.Props["B"]
+	if !black {
+		if v, ok = n.Props["W"]; !ok {
+			return Move{}, false, false, nil
+		}
+	}
+	if len(v) == 0 {
+		return Move{}, false, false, fmt.Errorf("empty move property")
+	}
+	m, err = ParseSGFMove(v[0], size)
+	return m, black, err == nil, err
+}
+
+// sgfSize returns the board size of the game tree with the given root: its SZ property, or 19 if it has none.
+// Boards bigger than max x max are rejected before creating any game.
+func sgfSize(root *SGFNode, max int) (int, error) {
+	sz := root.Prop("SZ")
+	if sz == "" {
+		return defaultSGFSize, nil
+	}
+	size, err := strconv.Atoi(strings.Split(sz, ":")[0])
+	if err != nil || size < 2 || size > max {
+		return 0, fmt.Errorf("invalid board size %q, must be between 2 and %v", sz, max)
+	}
+	return size, nil
+}
+
+// ParseSGF parses an SGF collection and returns the root node of each game tree.
+func ParseSGF(s string) ([]*SGFNode, error) {
+	p := &sgfParser{s: s}
+	roots := make([]*SGFNode, 0)
+	for {
+		p.skipSpaces()
+		if p.i >= len(p.s) {
+			break
+		}
+		root, err := p.gameTree(nil)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, root)
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("sgf without game trees")
+	}
+	return roots, nil
+}
+
+type sgfParser struct {
+	s string
+	i int
+}
+
+func (p *sgfParser) skipSpaces() {
+	for p.i < len(p.s) && strings.ContainsRune(" \t\r\n", rune(p.s[p.i])) {
+		p.i++
+	}
+}
+
+func (p *sgfParser) expect(c byte) error {
+	p.skipSpaces()
+	if p.i >= len(p.s) || p.s[p.i] != c {
+		return fmt.Errorf("sgf syntax error at %v: expected %q", p.i, c)
+	}
+	p.i++
+	return nil
+}
+
+// gameTree parses "(" Sequence GameTree* ")" and returns the first node of the sequence.
+func (p *sgfParser) gameTree(parent *SGFNode) (*SGFNode, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	var first, last *SGFNode
+	for {
+		p.skipSpaces()
+		if p.i >= len(p.s) || p.s[p.i] != ';' {
+			break
+		}
+		p.i++
+		n, err := p.node()
+		if err != nil {
+			return nil, err
+		}
+		if last == nil {
+			first = n
+			n.Parent = parent
+			if parent != nil {
+				parent.Children = append(parent.Children, n)
+			}
+		} else {
+			n.Parent = last
+			last.Children = append(last.Children, n)
+		}
+		last = n
+	}
+	if first == nil {
+		return nil, fmt.Errorf("sgf syntax error at %v: empty sequence", p.i)
+	}
+	for {
+		p.skipSpaces()
+		if p.i >= len(p.s) || p.s[p.i] != '(' {
+			break
+		}
+		if _, err := p.gameTree(last); err != nil {
+			return nil, err
+		}
+	}
+	return first, p.expect(')')
+}
+
+func (p *sgfParser) node() (*SGFNode, error) {
+	n := &SGFNode{Props: make(map[string][]string)}
+	for {
+		p.skipSpaces()
+		start := p.i
+		for p.i < len(p.s) && p.s[p.i] >= 'A' && p.s[p.i] <= 'Z' {
+			p.i++
+		}
+		if start == p.i {
+			return n, nil
+		}
+		id := p.s[start:p.i]
+		values := make([]string, 0, 1)
+		for {
+			p.skipSpaces()
+			if p.i >= len(p.s) || p.s[p.i] != '[' {
+				break
+			}
+			p.i++
+			var sb strings.Builder
+			for ; p.i < len(p.s) && p.s[p.i] != ']'; p.i++ {
+				if p.s[p.i] == '\\' && p.i+1 < len(p.s) {
+					p.i++
+				}
+				sb.WriteByte(p.s[p.i])
+			}
+			if p.i >= len(p.s) {
+				return nil, fmt.Errorf("sgf syntax error: unfinished value of property %s", id)
+			}
+			p.i++
+			values = append(values, sb.String())
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("sgf syntax error at %v: property %s without values", p.i, id)
+		}
+		n.Props[id] = append(n.Props[id], values...)
+	}
+}
+
+// ParseSGFMove converts an SGF point ("cd" is column c, row d, from the top left corner) to a move.
+// An empty value, or "tt" in boards up to 19x19, is a pass.
+func ParseSGFMove(v string, size int) (Move, error) {
+	if v == "" || (v == "tt" && size <= 19) {
+		return Move{Pass: true}, nil
+	}
+	c, err := ParseSGFCoord(v, size)
+	return Move{X: c.X, Y: c.Y}, err
+}
+
+// ParseSGFCoord converts an SGF point to a position of the board.
+func ParseSGFCoord(v string, size int) (Coord, error) {
+	if len(v) != 2 {
+		return Coord{}, fmt.Errorf("invalid sgf point %q", v)
+	}
+	x, y := sgfLetterValue(v[1]), sgfLetterValue(v[0])
+	if x < 0 || x >= size || y < 0 || y >= size {
+		return Coord{}, fmt.Errorf("invalid sgf point %q for size %v", v, size)
+	}
+	return Coord{X: x, Y: y}, nil
+}
+
+// SGFPoint converts a position of the board to an SGF point.
+func SGFPoint(c Coord) string {
+	return string(sgfLetters[c.Y]) + string(sgfLetters[c.X])
+}
+
+const sgfLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+func sgfLetterValue(c byte) int {
+	return strings.IndexByte(sgfLetters, c)
+}
@@ -0,0 +1,66 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSGF(t *testing.T) {
+	assert := assert.New(t)
+	roots, err := ParseSGF(`(;GM[1]SZ[9]C[a \] escaped]AB[aa][bb]
+		;B[cd];W[]
+		(;B[ee]C[main])
+		(;B[ff]))
+		(;SZ[5])`)
+	assert.NoError(err)
+	assert.Len(roots, 2)
+	root := roots[0]
+	assert.Equal("9", root.Prop("SZ"))
+	assert.Equal("a ] escaped", root.Prop("C"))
+	assert.Equal([]string{"aa", "bb"}, root.Props["AB"])
+	assert.Equal("", root.Prop("PL"))
+	assert.Len(root.Children, 1)
+	n := root.Children[0]
+	assert.Equal(root, n.Parent)
+	m, black, ok, err := n.Move(9)
+	assert.True(ok)
+	assert.NoError(err)
+	assert.True(black)
+	assert.Equal(Move{X: 3, Y: 2}, m)
+	n = n.Children[0]
+	m, black, ok, _ = n.Move(9)
+	assert.True(ok)
+	assert.False(black)
+	assert.True(m.Pass)
+	assert.Len(n.Children, 2)
+	assert.Equal("main", n.Children[0].Prop("C"))
+	m, _, _, _ = n.Children[1].Move(9)
+	assert.Equal(Move{X: 5, Y: 5}, m)
+	_, _, ok, err = root.Move(9)
+	assert.False(ok)
+	assert.NoError(err)
+
+	for _, s := range []string{"", "(;B[aa]", "(;B)", ";B[aa]", "(;C[unfinished)", "()"} {
+		_, err = ParseSGF(s)
+		assert.Error(err, s)
+	}
+}
+
+func TestSGFCoords(t *testing.T) {
+	assert := assert.New(t)
+	c, err := ParseSGFCoord("sd", 19)
+	assert.NoError(err)
+	assert.Equal(Coord{X: 3, Y: 18}, c)
+	assert.Equal("sd", SGFPoint(c))
+	_, err = ParseSGFCoord("ff", 5)
+	assert.Error(err)
+	_, err = ParseSGFCoord("a", 5)
+	assert.Error(err)
+	m, err := ParseSGFMove("tt", 19)
+	assert.NoError(err)
+	assert.True(m.Pass)
+	points, err := sgfPointList("ab:bc", 5)
+	assert.NoError(err)
+	assert.Equal([]Coord{{1, 0}, {1, 1}, {2, 0}, {2, 1}}, points)
+}
@@ -0,0 +1,173 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// Symmetry is one of the eight symmetries of the square board, rotations and reflections, that can be
+// combined with SwapColors to also exchange black and white.
+type Symmetry int
+
+const (
+	Identity       Symmetry = iota
+	Rotate90                // Clockwise.
+	Rotate180               // Half turn.
+	Rotate270               // Clockwise, so 90 degrees counterclockwise.
+	FlipVertical            // Top and bottom rows swapped.
+	FlipHorizontal          // Left and right columns swapped.
+	Transpose               // Reflection on the diagonal from the top left corner.
+	AntiTranspose           // Reflection on the diagonal from the top right corner.
+)
+
+// SwapColors is combined with the board symmetries with |, like Rotate90 | SwapColors.
+const SwapColors Symmetry = 8
+
+// Symmetries lists the eight board symmetries, without color swap.
+var Symmetries = []Symmetry{Identity, Rotate90, Rotate180, Rotate270, FlipVertical, FlipHorizontal, Transpose, AntiTranspose}
+
+// Coord returns the position c after applying the symmetry in a size x size board.
+func (s Symmetry) Coord(c Coord, size int) Coord {
+	n := size - 1
+	switch s &^ SwapColors {
+	case Rotate90:
+		return Coord{c.Y, n - c.X}
+	case Rotate180:
+		return Coord{n - c.X, n - c.Y}
+	case Rotate270:
+		return Coord{n - c.Y, c.X}
+	case FlipVertical:
+		return Coord{n - c.X, c.Y}
+	case FlipHorizontal:
+		return Coord{c.X, n - c.Y}
+	case Transpose:
+		return Coord{c.Y, c.X}
+	case AntiTranspose:
+		return Coord{n - c.Y, n - c.X}
+	default:
+		return c
+	}
+}
+
+// Moves returns the move sequence after applying the symmetry. Passes are not changed.
+func (s Symmetry) Moves(moves []Move, size int) []Move {
+	result := make([]Move, len(moves))
+	for i, m := range moves {
+		if m.Pass {
+			result[i] = m
+			continue
+		}
+		c := s.Coord(Coord{m.X, m.Y}, size)
+		result[i] = Move{X: c.X, Y: c.Y}
+	}
+	return result
+}
+
+// Inverse returns the symmetry that undoes s.
+func (s Symmetry) Inverse() Symmetry {
+	switch s &^ SwapColors {
+	case Rotate90:
+		return Rotate270 | (s & SwapColors)
+	case Rotate270:
+		return Rotate90 | (s & SwapColors)
+	default:
+		return s
+	}
+}
+
+// Transform returns a copy of the game with the symmetry applied to the position and its history. With
+// SwapColors, the stones, the captures and the turn of black and white are exchanged.
+// Only games on the square grid or the torus can be transformed, and colors are only swapped in games of black and white.
+func (g *GoGame) Transform(s Symmetry) (*GoGame, error) {
+	if err := g.checkSymmetric(s); err != nil {
+		return nil, err
+	}
+	t := g.Clone()
+	stones, _ := g.transformedStones(s)
+	for i, st := range stones {
+		t.board.field[i/t.board.size][i%t.board.size].State = st
+	}
+	t.board.rebuildChains()
+	for _, moves := range [][]RecordMove{t.setup, t.history} {
+		for i, m := range moves {
+			moves[i].Move = s.Moves([]Move{m.Move}, t.board.size)[0]
+			if s&SwapColors != 0 {
+				moves[i].Black = !m.Black
+			}
+		}
+	}
+	if s&SwapColors != 0 {
+		t.BlackPlayedLast = !t.BlackPlayedLast
+		t.BlackCaptures, t.WhiteCaptures = t.WhiteCaptures, t.BlackCaptures
+	}
+	return t, nil
+}
+
+// Canonical returns the symmetry taking the position to its canonical form, and the Zobrist hash of that
+// form. Equivalent positions have the same canonical form whatever their orientation, and also whatever their
+// colors if swapColors is true. The canonical form is the smallest of the transformed positions, comparing
+// the stones in the order of GoGame.String and then the player in turn.
+func (g *GoGame) Canonical(swapColors bool) (Symmetry, uint64, error) {
+	syms := slices.Clone(Symmetries)
+	if swapColors {
+		for _, s := range Symmetries {
+			syms = append(syms, s|SwapColors)
+		}
+	}
+	if err := g.checkSymmetric(syms[len(syms)-1]); err != nil {
+		return Identity, 0, err
+	}
+	best := Identity
+	var bestStones []pointStateType
+	var bestNext int
+	bestKey := ""
+	for _, s := range syms {
+		stones, next := g.transformedStones(s)
+		key := make([]byte, len(stones), len(stones)+1)
+		for i, st := range stones {
+			key[i] = stateLetters[st]
+		}
+		key = strconv.AppendInt(key, int64(next), 10)
+		if bestStones == nil || string(key) < bestKey {
+			best, bestStones, bestNext, bestKey = s, stones, next, string(key)
+		}
+	}
+	return best, zobristHash(bestStones, bestNext, g.board.size), nil
+}
+
+// transformedStones returns the stones by flat index and the player in turn after applying the symmetry.
+func (g *GoGame) transformedStones(s Symmetry) ([]pointStateType, int) {
+	size := g.board.size
+	stones := make([]pointStateType, size*size)
+	for x := range g.board.field {
+		for y := range g.board.field[x] {
+			st := g.board.field[x][y].State
+			if s&SwapColors != 0 && st != FREE {
+				st = opponent(st)
+			}
+			c := s.Coord(Coord{x, y}, size)
+			stones[c.X*size+c.Y] = st
+		}
+	}
+	next := g.next()
+	if s&SwapColors != 0 {
+		next = 1 - next
+	}
+	return stones, next
+}
+
+func (g *GoGame) checkSymmetric(s Symmetry) error {
+	switch g.board.topology.(type) {
+	case Grid, Torus:
+	default:
+		return fmt.Errorf("symmetries are only supported on the square grid or the torus")
+	}
+	if s&SwapColors != 0 && g.Colors > 0 {
+		return fmt.Errorf("colors can not be swapped in multi-color games")
+	}
+	if s < 0 || s > (AntiTranspose|SwapColors) {
+		return fmt.Errorf("invalid symmetry %v", int(s))
+	}
+	return nil
+}
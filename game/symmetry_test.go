@@ -0,0 +1,99 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymmetryCoords(t *testing.T) {
+	assert := assert.New(t)
+	c := Coord{0, 1}
+	expected := map[Symmetry]Coord{
+		Identity:       {0, 1},
+		Rotate90:       {1, 4},
+		Rotate180:      {4, 3},
+		Rotate270:      {3, 0},
+		FlipVertical:   {4, 1},
+		FlipHorizontal: {0, 3},
+		Transpose:      {1, 0},
+		AntiTranspose:  {3, 4},
+	}
+	for s, e := range expected {
+		assert.Equal(e, s.Coord(c, 5), s)
+		assert.Equal(e, (s|SwapColors).Coord(c, 5), s)
+		assert.Equal(c, s.Inverse().Coord(s.Coord(c, 5), 5), s)
+	}
+	moves := Rotate90.Moves([]Move{{X: 0, Y: 0}, {Pass: true}}, 5)
+	assert.Equal([]Move{{X: 0, Y: 4}, {Pass: true}}, moves)
+}
+
+func TestTransform(t *testing.T) {
+	assert := assert.New(t)
+	g := newGameFromRows(t,
+		"BW*",
+		"B**",
+		"***",
+	)
+	r, err := g.Transform(Rotate90)
+	assert.NoError(err)
+	assert.Equal("*BB**W***", r.String())
+	assert.NoError(r.Validate())
+	assert.Equal(g.BlackPlayedLast, r.BlackPlayedLast)
+	assert.Equal(Rotate90.Moves([]Move{g.history[0].Move}, 3)[0], r.History()[0].Move)
+	assert.NoError(r.Play(2, 2, !r.BlackPlayedLast))
+	assert.Equal("BW*B*****", g.String())
+
+	s, err := g.Transform(FlipHorizontal | SwapColors)
+	assert.NoError(err)
+	assert.Equal("*BW**W***", s.String())
+	assert.Equal(!g.BlackPlayedLast, s.BlackPlayedLast)
+	assert.NoError(s.Validate())
+
+	_, err = g.Transform(Symmetry(16))
+	assert.Error(err)
+	c, _ := NewGameWithTopology(3, Cylinder{})
+	_, err = c.Transform(Rotate90)
+	assert.Error(err)
+}
+
+func TestCanonical(t *testing.T) {
+	assert := assert.New(t)
+	g := newGameFromRows(t,
+		"B****",
+		"*****",
+		"*****",
+		"*****",
+		"*****",
+	)
+	sym, hash, err := g.Canonical(false)
+	assert.NoError(err)
+	canonical, _ := g.Transform(sym)
+	assert.Equal(hash, canonical.Hash())
+	for _, s := range Symmetries {
+		r, _ := g.Transform(s)
+		_, h, _ := r.Canonical(false)
+		assert.Equal(hash, h, s)
+		_, h, _ = r.Canonical(true)
+		_, h2, _ := g.Canonical(true)
+		assert.Equal(h2, h, s)
+	}
+	swapped, _ := g.Transform(Rotate90 | SwapColors)
+	_, h, _ := swapped.Canonical(false)
+	assert.NotEqual(hash, h)
+	_, h, _ = swapped.Canonical(true)
+	_, h2, _ := g.Canonical(true)
+	assert.Equal(h2, h)
+
+	other := newGameFromRows(t,
+		"*B***",
+		"*****",
+		"*****",
+		"*****",
+		"*****",
+	)
+	_, h, _ = other.Canonical(true)
+	assert.NotEqual(h2, h)
+	empty, _ := NewGame(5)
+	assert.Equal(uint64(0), empty.Hash())
+}
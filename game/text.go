@@ -0,0 +1,131 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// textColumns names the columns from the left, skipping I as usual in Go. Rows are numbered from the bottom.
+const textColumns = "ABCDEFGHJKLMNOPQRSTUVWXYZ"
+
+// SplitRows separates the rows of a board status in the format of GoGame.String with \n characters.
+func SplitRows(status string, size int) string {
+	var sb strings.Builder
+	for x := 0; x*size < len(status); x++ {
+		if x > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(status[x*size : min((x+1)*size, len(status))])
+	}
+	return sb.String()
+}
+
+// Rows returns the board status like String, with the rows separated by \n characters.
+func (g *GoGame) Rows() string {
+	return SplitRows(g.String(), g.board.size)
+}
+
+// Hoshi returns the star points of the board: the corner ones, and the center and sides in odd boards.
+func Hoshi(size int) []Coord {
+	points := make([]Coord, 0, 9)
+	edge := 3
+	if size < 13 {
+		edge = 2
+	}
+	if size >= 7 {
+		for _, x := range []int{edge, size - 1 - edge} {
+			for _, y := range []int{edge, size - 1 - edge} {
+				points = append(points, Coord{x, y})
+			}
+		}
+	}
+	if size%2 == 1 {
+		mid := size / 2
+		points = append(points, Coord{mid, mid})
+		if size >= 15 {
+			points = append(points, Coord{edge, mid}, Coord{size - 1 - edge, mid}, Coord{mid, edge}, Coord{mid, size - 1 - edge})
+		}
+	}
+	return points
+}
+
+// Text returns a diagram of the position for humans, with the column letters and the row numbers around the board,
+// '.' for the empty points, '+' for the star points, the stones with the letters of String, the last move
+// between parentheses, and the captured stones of each color below. Boards up to 25 x 25 have column letters.
+//
+//	  A B C D E
+//	5 . . . . . 5
+//	4 . B . . . 4
+//	3 . . +(W). 3
+//	2 . . . . . 2
+//	1 . . . . . 1
+//	  A B C D E
+//	Captured: black 0, white 0
+func (g *GoGame) Text() string {
+	size := g.board.size
+	stones := g.String()
+	hoshi := make(map[int]bool)
+	for _, h := range Hoshi(size) {
+		hoshi[h.X*size+h.Y] = true
+	}
+	last := -1
+	if m, ok := g.LastMove(); ok && !m.Pass {
+		last = m.X*size + m.Y
+	}
+	width := len(strconv.Itoa(size))
+	var sb strings.Builder
+	header := func() {
+		sb.WriteString(strings.Repeat(" ", width))
+		for y := 0; y < size; y++ {
+			column := "?"
+			if y < len(textColumns) {
+				column = textColumns[y : y+1]
+			}
+			sb.WriteString(" " + column)
+		}
+		sb.WriteByte('\n')
+	}
+	header()
+	for x := 0; x < size; x++ {
+		row := strconv.Itoa(size - x)
+		fmt.Fprintf(&sb, "%*s", width, row)
+		for y := 0; y < size; y++ {
+			i := x*size + y
+			point := stones[i : i+1]
+			if point == "*" {
+				point = "."
+				if hoshi[i] {
+					point = "+"
+				}
+			}
+			switch {
+			case i == last:
+				sb.WriteString("(" + point)
+			case i == last+1 && y > 0:
+				sb.WriteString(")" + point)
+			default:
+				sb.WriteString(" " + point)
+			}
+		}
+		if last == x*size+size-1 {
+			sb.WriteByte(')')
+		} else {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(row + "\n") //not padded, so the lines have no trailing spaces
+	}
+	header()
+	sb.WriteString("Captured:")
+	if g.Colors > 0 {
+		for p, n := range g.Captures {
+			if p > 0 {
+				sb.WriteByte(',')
+			}
+			fmt.Fprintf(&sb, " %s %v", ColorName(p), n)
+		}
+	} else {
+		fmt.Fprintf(&sb, " %s %v, %s %v", ColorName(0), g.BlackCaptures, ColorName(1), g.WhiteCaptures)
+	}
+	return sb.String()
+}
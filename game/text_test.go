@@ -0,0 +1,65 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitRows(t *testing.T) {
+	assert := assert.New(t)
+	g := newGameFromRows(t,
+		"B**",
+		"*W*",
+		"***",
+	)
+	assert.Equal("B**\n*W*\n***", g.Rows())
+	assert.Equal("B**\n*W*\n***", SplitRows(g.String(), 3))
+	assert.Equal("", SplitRows("", 3))
+}
+
+func TestHoshi(t *testing.T) {
+	assert := assert.New(t)
+	assert.Len(Hoshi(19), 9)
+	assert.Contains(Hoshi(19), Coord{3, 9})
+	assert.Len(Hoshi(13), 5)
+	assert.Len(Hoshi(9), 5)
+	assert.Contains(Hoshi(9), Coord{2, 6})
+	assert.Equal([]Coord{{2, 2}}, Hoshi(5))
+	assert.Empty(Hoshi(4))
+}
+
+func TestText(t *testing.T) {
+	assert := assert.New(t)
+	g, _ := NewGame(5)
+	assert.NoError(g.Play(1, 1, true))
+	assert.NoError(g.Play(2, 3, false))
+	assert.Equal(strings.Join([]string{
+		"  A B C D E",
+		"5 . . . . . 5",
+		"4 . B . . . 4",
+		"3 . . +(W). 3",
+		"2 . . . . . 2",
+		"1 . . . . . 1",
+		"  A B C D E",
+		"Captured: black 0, white 0",
+	}, "\n"), g.Text())
+
+	assert.NoError(g.Play(4, 4, true))
+	assert.Contains(g.Text(), "1 . . . .(B)1")
+
+	big, _ := NewGame(10)
+	assert.NoError(big.Play(0, 0, true))
+	lines := strings.Split(big.Text(), "\n")
+	assert.Equal("   A B C D E F G H J K", lines[0])
+	assert.Equal("10(B). . . . . . . . . 10", lines[1])
+	assert.Equal(" 1 . . . . . . . . . . 1", lines[10])
+	for _, line := range lines {
+		assert.Equal(strings.TrimRight(line, " "), line)
+	}
+
+	m, _ := NewMultiColorGame(5, 3)
+	assert.NoError(m.PlayColor(0, 0, 0))
+	assert.True(strings.HasSuffix(m.Text(), "Captured: black 0, white 0, red 0"))
+}
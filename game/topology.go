@@ -0,0 +1,93 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Topology decides which points of a size x size board are neighbours. Chains, liberties and captures
+// work the same on any topology.
+//
+// Analysis that reasons about the edges of the board, like false eyes, influence or pass-alive regions,
+// assumes the square grid.
+type Topology interface {
+	Neighbors(c Coord, size int) []Coord
+}
+
+// Grid is the usual square grid, with edges and corners.
+type Grid struct{}
+
+// Torus is a grid where both the rows and the columns wrap around, so there are no edges.
+type Torus struct{}
+
+// Cylinder is a grid where the columns wrap around, joining the left and right edges.
+type Cylinder struct{}
+
+// Graph is a custom topology given by the neighbours of each point. It must be symmetric, and points
+// without neighbours can never be played.
+type Graph map[Coord][]Coord
+
+func (Grid) Neighbors(c Coord, size int) []Coord {
+	return wrappedNeighbors(c, size, false, false)
+}
+
+func (Torus) Neighbors(c Coord, size int) []Coord {
+	return wrappedNeighbors(c, size, true, true)
+}
+
+func (Cylinder) Neighbors(c Coord, size int) []Coord {
+	return wrappedNeighbors(c, size, false, true)
+}
+
+func (g Graph) Neighbors(c Coord, size int) []Coord {
+	return g[c]
+}
+
+// wrappedNeighbors returns the neighbours in the grid right, left, top and bottom, wrapping the rows and
+// the columns if asked.
+func wrappedNeighbors(c Coord, size int, wrapRows, wrapColumns bool) []Coord {
+	nbrs := make([]Coord, 0, 4)
+	for _, d := range [][2]int{{0, 1}, {0, -1}, {-1, 0}, {1, 0}} {
+		x, y := c.X+d[0], c.Y+d[1]
+		if wrapRows {
+			x = (x + size) % size
+		}
+		if wrapColumns {
+			y = (y + size) % size
+		}
+		if x >= 0 && x < size && y >= 0 && y < size {
+			nbrs = append(nbrs, Coord{x, y})
+		}
+	}
+	return nbrs
+}
+
+// neighborsOf returns the neighbours of the point given by the topology, without repetitions nor the
+// point itself, which happen in very small boards that wrap around.
+func neighborsOf(t Topology, c Coord, size int) []Coord {
+	nbrs := make([]Coord, 0, 4)
+	for _, n := range t.Neighbors(c, size) {
+		if n != c && !slices.Contains(nbrs, n) {
+			nbrs = append(nbrs, n)
+		}
+	}
+	return nbrs
+}
+
+// validateTopology checks that every neighbour is inside the board, and that the topology is symmetric.
+func validateTopology(t Topology, size int) error {
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			c := Coord{x, y}
+			for _, n := range neighborsOf(t, c, size) {
+				if n.X < 0 || n.X >= size || n.Y < 0 || n.Y >= size {
+					return fmt.Errorf("invalid topology: neighbour (%v, %v) of (%v, %v) is out of the board", n.X, n.Y, x, y)
+				}
+				if !slices.Contains(neighborsOf(t, n, size), c) {
+					return fmt.Errorf("invalid topology: (%v, %v) is neighbour of (%v, %v), but not the other way around", n.X, n.Y, x, y)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTorus(t *testing.T) {
+	assert := assert.New(t)
+	g, err := NewGameWithTopology(5, Torus{})
+	assert.NoError(err)
+	assert.Len(g.board.field[0][0].neighbords, 4)
+	assert.Contains(g.board.field[0][0].neighbords, &g.board.field[4][0])
+	assert.Contains(g.board.field[0][0].neighbords, &g.board.field[0][4])
+	//black captures the white stone in the corner from the other side of the board
+	assert.NoError(g.Play(0, 1, true))
+	assert.NoError(g.Play(0, 0, false))
+	assert.NoError(g.Play(1, 0, true))
+	assert.NoError(g.Pass(false))
+	assert.NoError(g.Play(4, 0, true))
+	assert.NoError(g.Pass(false))
+	assert.Equal(1, g.board.chains[g.board.field[0][0].chainId()].liberties)
+	assert.NoError(g.Play(0, 4, true))
+	assert.Equal(1, g.WhiteCaptures)
+	assert.NoError(g.Validate())
+
+	fb := g.FastBoard()
+	assert.Equal(4, fb.Liberties(4, 0))
+	_, err = fb.Play(0, 0, false) //surrounded in the torus
+	assert.Error(err)
+	_, err = NewFastBoard(5).Play(0, 0, false)
+	assert.NoError(err)
+}
+
+func TestTorusEye(t *testing.T) {
+	//the eye in (0, 0) has four diagonals in the torus, so one white stone does not make it false
+	assert := assert.New(t)
+	g, _ := NewGameWithTopology(5, Torus{})
+	assert.NoError(g.Play(0, 1, true))
+	assert.NoError(g.Play(1, 1, false))
+	for _, c := range []Coord{{1, 0}, {4, 0}, {0, 4}} {
+		assert.NoError(g.Play(c.X, c.Y, true))
+		assert.NoError(g.Pass(false))
+	}
+	eyes, err := g.Eyes(0, 1)
+	assert.NoError(err)
+	assert.Contains(eyes, EyeRegion{Points: []Coord{{0, 0}}, Kind: RealEye})
+	assert.NoError(g.Pass(true))
+	assert.NoError(g.Play(4, 4, false))
+	eyes, _ = g.Eyes(0, 1)
+	assert.Contains(eyes, EyeRegion{Points: []Coord{{0, 0}}, Kind: FalseEye})
+}
+
+func TestCylinder(t *testing.T) {
+	assert := assert.New(t)
+	g, err := NewGameWithTopology(5, Cylinder{})
+	assert.NoError(err)
+	assert.Len(g.board.field[0][0].neighbords, 3)
+	assert.Contains(g.board.field[0][0].neighbords, &g.board.field[0][4])
+	assert.NotContains(g.board.field[0][0].neighbords, &g.board.field[4][0])
+	assert.Len(g.board.field[2][0].neighbords, 4)
+	g, _ = NewGameWithTopology(2, Torus{})
+	assert.Len(g.board.field[0][0].neighbords, 2)
+}
+
+func TestGraph(t *testing.T) {
+	assert := assert.New(t)
+	//a ring of four points in the first row, the other points can not be played
+	ring := Graph{
+		{0, 0}: {{0, 1}, {0, 3}},
+		{0, 1}: {{0, 0}, {0, 2}},
+		{0, 2}: {{0, 1}, {0, 3}},
+		{0, 3}: {{0, 2}, {0, 0}},
+	}
+	g, err := NewGameWithTopology(4, ring)
+	assert.NoError(err)
+	assert.Equal(Graph{}.Neighbors(Coord{0, 0}, 4), []Coord(nil))
+	assert.Error(g.Play(2, 2, true))
+	assert.NoError(g.Play(0, 0, true))
+	assert.NoError(g.Play(0, 1, false))
+	assert.NoError(g.Play(0, 2, true)) //(0, 1) only has two neighbours
+	assert.Equal(1, g.WhiteCaptures)
+	assert.Error(g.Play(0, 3, false)) //self-capture
+	assert.Error(g.Play(0, 1, false))
+	assert.NoError(g.Validate())
+
+	_, err = NewGameWithTopology(4, Graph{{0, 0}: {{0, 1}}})
+	assert.Error(err) //not symmetric
+	_, err = NewGameWithTopology(4, Graph{{0, 0}: {{0, 4}}})
+	assert.Error(err) //out of the board
+}
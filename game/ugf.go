@@ -0,0 +1,137 @@
+package game
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseUGF reads a game record in the UGF format of Pandanet. The file has INI-like sections: [Header] with
+// lines like "PlayerB=name,rank,..." and "Hdcp=handicap,komi", and [Data] with a move per line like
+// "QD,B1,0": the column from the left and the row from the bottom, both as letters from A, the color and the
+// number of the move. Stones numbered 0 are the handicap stones, placed in their usual places when they are
+// not listed, and points outside the board are passes. The moves are replayed: an illegal one is returned as an
+// error, together with the record.
+func ParseUGF(s string) (*Record, error) {
+	r := &Record{Size: defaultSGFSize}
+	handicap := 0
+	section := ""
+	var moves []string
+	sc := bufio.NewScanner(strings.NewReader(s))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(line[1 : len(line)-1])
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		if section == "data" {
+			moves = append(moves, line)
+			continue
+		}
+		if section != "header" {
+			continue
+		}
+		key, value, _ := strings.Cut(line, "=")
+		fields := strings.Split(value, ",")
+		var err error
+		switch strings.ToLower(key) {
+		case "size":
+			if r.Size, err = strconv.Atoi(value); err != nil {
+				return nil, fmt.Errorf("invalid board size %q", value)
+			}
+		case "hdcp":
+			if handicap, err = strconv.Atoi(fields[0]); err != nil {
+				return nil, fmt.Errorf("invalid handicap %q", value)
+			}
+			if len(fields) > 1 {
+				if r.Komi, err = strconv.ParseFloat(fields[1], 64); err != nil {
+					return nil, fmt.Errorf("invalid komi %q", value)
+				}
+			}
+		case "playerb":
+			r.BlackPlayer = fields[0]
+			if len(fields) > 1 {
+				r.BlackRank = fields[1]
+			}
+		case "playerw":
+			r.WhitePlayer = fields[0]
+			if len(fields) > 1 {
+				r.WhiteRank = fields[1]
+			}
+		case "winner":
+			if r.Result, err = ugfResult(fields); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.checkSize(); err != nil {
+		return nil, err
+	}
+	for _, line := range moves {
+		fields := strings.Split(line, ",")
+		if len(fields) < 2 || len(fields[0]) != 2 || len(fields[1]) < 1 {
+			return nil, fmt.Errorf("invalid move %q", line)
+		}
+		black := fields[1][0] == 'B'
+		if !black && fields[1][0] != 'W' {
+			return nil, fmt.Errorf("invalid color in move %q", line)
+		}
+		y, x := int(fields[0][0]-'A'), r.Size-1-int(fields[0][1]-'A')
+		m := Move{X: x, Y: y}
+		if x < 0 || x >= r.Size || y < 0 || y >= r.Size {
+			m = Move{Pass: true}
+		}
+		if fields[1][1:] == "0" {
+			if m.Pass {
+				return nil, fmt.Errorf("invalid handicap stone %q", line)
+			}
+			if black {
+				r.BlackSetup = append(r.BlackSetup, Coord{m.X, m.Y})
+			} else {
+				r.WhiteSetup = append(r.WhiteSetup, Coord{m.X, m.Y})
+			}
+			continue
+		}
+		r.Moves = append(r.Moves, RecordMove{Move: m, Black: black})
+	}
+	if err := r.setHandicap(handicap); err != nil {
+		return nil, err
+	}
+	return r.checked()
+}
+
+// ugfResult converts the winner of an UGF record, like "B,3.5", "W,C" for resignation or "B,T" for time,
+// to SGF notation.
+func ugfResult(fields []string) (string, error) {
+	winner := strings.ToUpper(strings.TrimSpace(fields[0]))
+	how := ""
+	if len(fields) > 1 {
+		how = strings.ToUpper(strings.TrimSpace(fields[1]))
+	}
+	switch {
+	case winner == "" || winner == "-" || winner == "?":
+		return "", nil
+	case winner == "D" || winner == "0" || winner == "JIGO":
+		return "0", nil
+	case winner != "B" && winner != "W":
+		return "", fmt.Errorf("invalid winner %q", strings.Join(fields, ","))
+	case how == "C" || how == "R":
+		return winner + "+R", nil
+	case how == "T":
+		return winner + "+T", nil
+	case how == "":
+		return winner + "+", nil
+	}
+	score, err := strconv.ParseFloat(how, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid winner %q", strings.Join(fields, ","))
+	}
+	return scoreResult(winner == "B", score), nil
+}
@@ -0,0 +1,64 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUGF(t *testing.T) {
+	assert := assert.New(t)
+	r, err := ParseUGF(`[Header]
+Lang=JP
+PlayerB=Ana,3k,,
+PlayerW=Bruno,1d,,
+Size=9
+Hdcp=0,6.5
+Winner=W,C
+[Data]
+EE,B1,0
+CC,W2,0
+YA,B3,0
+DC,W4,0
+[Figure]
+`)
+	assert.NoError(err)
+	assert.Equal(9, r.Size)
+	assert.Equal(6.5, r.Komi)
+	assert.Equal("Ana", r.BlackPlayer)
+	assert.Equal("3k", r.BlackRank)
+	assert.Equal("Bruno", r.WhitePlayer)
+	assert.Equal("1d", r.WhiteRank)
+	assert.Equal("W+R", r.Result)
+	assert.Empty(r.BlackSetup)
+	assert.Equal([]RecordMove{
+		{Move: Move{X: 4, Y: 4}, Black: true},
+		{Move: Move{X: 6, Y: 2}},
+		{Move: Move{Pass: true}, Black: true},
+		{Move: Move{X: 6, Y: 3}},
+	}, r.Moves)
+
+	//handicap stones in their usual places
+	r, err = ParseUGF("[Header]\nSize=19\nHdcp=2,0.5\nWinner=B,3.5\n[Data]\nCC,W1,0\n")
+	assert.NoError(err)
+	assert.Equal(0.5, r.Komi)
+	assert.Equal("B+3.5", r.Result)
+	assert.Equal([]Coord{{3, 15}, {15, 3}}, r.BlackSetup)
+	_, err = ParseUGF("[Header]\nSize=19\nHdcp=2,0.5\n[Data]\nDD,W1,0\n")
+	assert.ErrorContains(err, "illegal move 1")
+
+	//or listed as stones numbered 0
+	r, err = ParseUGF("[Header]\nSize=19\nHdcp=2,0.5\n[Data]\nDD,B0,0\nPP,B0,0\nQD,W1,0\n")
+	assert.NoError(err)
+	assert.Equal([]Coord{{15, 3}, {3, 15}}, r.BlackSetup)
+
+	r, err = ParseUGF("[Header]\nSize=9\n[Data]\nEE,B1,0\nEE,W2,0\n")
+	assert.ErrorContains(err, "illegal move 2")
+	assert.Len(r.Moves, 2)
+	_, err = ParseUGF("[Header]\nSize=9\n[Data]\nEE,X1,0\n")
+	assert.Error(err)
+	_, err = ParseUGF("[Header]\nSize=9\nWinner=Q,1\n")
+	assert.Error(err)
+	_, err = ParseUGF("[Header]\nSize=60000\n[Data]\n")
+	assert.ErrorContains(err, "invalid board size")
+}
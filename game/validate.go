@@ -0,0 +1,125 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// group is a set of connected stones of the same player found by flood fill,
+// independently of the chains tracked by the board.
+type group struct {
+	state  pointStateType
+	points map[*Point]bool
+	libs   map[*Point]bool
+}
+
+// floodFillGroups recomputes all the groups of the board from scratch.
+func floodFillGroups(b *board) []group {
+	seen := make(map[*Point]bool)
+	groups := make([]group, 0)
+	for x := range b.field {
+		for y := range b.field[x] {
+			start := &b.field[x][y]
+			if start.State == FREE || seen[start] {
+				continue
+			}
+			g := group{state: start.State, points: map[*Point]bool{start: true}, libs: make(map[*Point]bool)}
+			pending := []*Point{start}
+			seen[start] = true
+			for len(pending) > 0 {
+				p := pending[len(pending)-1]
+				pending = pending[:len(pending)-1]
+				for _, n := range p.neighbords {
+					if n.State == FREE {
+						g.libs[n] = true
+					} else if n.State == start.State && !seen[n] {
+						seen[n] = true
+						g.points[n] = true
+						pending = append(pending, n)
+					}
+				}
+			}
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+// Validate checks the consistency of the board. It recomputes every group and its liberties by flood fill
+// and reports all the differences with the chains tracked by the board. It returns nil if there are none.
+func (g *GoGame) Validate() error {
+	b := g.board
+	errs := make([]error, 0)
+	for x := range b.field {
+		for y := range b.field[x] {
+			p := &b.field[x][y]
+			if p.State == FREE && p.chainId() != 0 {
+				errs = append(errs, fmt.Errorf("free point (%v, %v) belongs to chain %v", x, y, p.chainId()))
+			}
+			if _, ok := b.chains[p.chainId()]; p.State != FREE && !ok {
+				errs = append(errs, fmt.Errorf("stone (%v, %v) belongs to missing chain %v", x, y, p.chainId()))
+			}
+		}
+	}
+	matched := make(map[int]bool)
+	for _, gr := range floodFillGroups(b) {
+		//the group is expected to be the chain most of its stones belong to
+		votes := make(map[int]int)
+		id := 0
+		for p := range gr.points {
+			votes[p.chainId()] += 1
+			if votes[p.chainId()] > votes[id] || (votes[p.chainId()] == votes[id] && p.chainId() < id) {
+				id = p.chainId()
+			}
+		}
+		c, ok := b.chains[id]
+		if !ok {
+			continue //already reported
+		}
+		if matched[id] {
+			errs = append(errs, fmt.Errorf("chain %v is split in several groups", id))
+			continue
+		}
+		matched[id] = true
+		if c.state != gr.state {
+			errs = append(errs, fmt.Errorf("chain %v has the wrong color", id))
+		}
+		stones := c.stones()
+		if len(stones) != len(gr.points) || c.count != len(stones) {
+			errs = append(errs, fmt.Errorf("chain %v has %v stones, but its group has %v", id, c.count, len(gr.points)))
+		}
+		if c.root == nil || c.root.chain() != c {
+			errs = append(errs, fmt.Errorf("chain %v is not the owner of its root", id))
+		}
+		for _, p := range stones {
+			if !gr.points[p] {
+				errs = append(errs, fmt.Errorf("chain %v contains stone (%v, %v) out of its group", id, p.X, p.Y))
+			}
+		}
+		for p := range gr.points {
+			if p.chainId() != id {
+				errs = append(errs, fmt.Errorf("stone (%v, %v) belongs to chain %v instead of chain %v", p.X, p.Y, p.chainId(), id))
+			}
+		}
+		if c.liberties != len(gr.libs) || len(c.libs) != len(gr.libs) {
+			errs = append(errs, fmt.Errorf("chain %v has %v liberties, but its group has %v", id, c.liberties, len(gr.libs)))
+		}
+		for l := range c.libs {
+			if !gr.libs[l] {
+				errs = append(errs, fmt.Errorf("chain %v has (%v, %v) as a wrong liberty", id, l.X, l.Y))
+			}
+		}
+	}
+	for id, c := range b.chains {
+		if c.id != id {
+			errs = append(errs, fmt.Errorf("chain %v is stored with id %v", c.id, id))
+		}
+		if c.board != b {
+			errs = append(errs, fmt.Errorf("chain %v does not belong to the board", id))
+		}
+		if !matched[id] {
+			errs = append(errs, fmt.Errorf("chain %v does not match any group", id))
+		}
+	}
+	return errors.Join(errs...)
+}
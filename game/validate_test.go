@@ -0,0 +1,62 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateReportsMismatches(t *testing.T) {
+	assert := assert.New(t)
+	g, _ := NewGame(5)
+	g.Play(1, 1, true)
+	g.Play(1, 2, false)
+	g.Play(2, 1, true)
+	assert.NoError(g.Validate())
+	g.board.chains[1].liberties = 4
+	assert.ErrorContains(g.Validate(), "chain 1 has 4 liberties, but its group has 5")
+	g.board.chains[1].updateLiberties()
+	g.board.field[2][1].parent = g.board.chains[2].root
+	assert.ErrorContains(g.Validate(), "stone (2, 1) belongs to chain 2 instead of chain 1")
+	g.board.field[2][1].parent = g.board.chains[1].root
+	g.board.field[3][3].parent = g.board.chains[2].root
+	assert.ErrorContains(g.Validate(), "free point (3, 3) belongs to chain 2")
+	g.board.field[3][3].parent = nil
+	g.board.chains[1].count = 3
+	assert.ErrorContains(g.Validate(), "chain 1 has 3 stones, but its group has 2")
+	g.board.chains[1].count = 2
+	g.board.field[0][0].State = BLACK
+	assert.ErrorContains(g.Validate(), "stone (0, 0) belongs to missing chain 0")
+	g.board.field[0][0].State = FREE
+	assert.NoError(g.Validate())
+}
+
+// FuzzPlay plays the moves encoded in the input, two bytes per move, and validates the board after every move.
+func FuzzPlay(f *testing.F) {
+	f.Add(uint8(5), []byte{1, 1, 1, 2, 2, 1, 2, 2, 3, 2, 2, 3, 3, 1, 4, 4})
+	f.Add(uint8(5), []byte{1, 2, 0, 2, 2, 1, 2, 0, 3, 2, 4, 2, 2, 3, 2, 4, 0, 0, 1, 1, 4, 0, 3, 1, 0, 4, 1, 3, 4, 4, 3, 3, 0, 1, 2, 2})
+	f.Add(uint8(2), []byte{0, 0, 1, 1, 0, 1, 1, 0, 0, 0})
+	f.Add(uint8(9), []byte{4, 4, 4, 5, 255, 255, 5, 5})
+	f.Fuzz(func(t *testing.T, size uint8, moves []byte) {
+		n := int(size%19) + 1
+		g, err := NewGame(n)
+		if err != nil {
+			return
+		}
+		black := true
+		for i := 0; i+1 < len(moves); i += 2 {
+			x, y := int(moves[i]), int(moves[i+1])
+			if x == 255 && y == 255 {
+				err = g.Pass(black)
+			} else {
+				err = g.Play(x%(n+1), y%(n+1), black) //n is out of the board
+			}
+			if err == nil {
+				black = !black
+			}
+			if err := g.Validate(); err != nil {
+				t.Fatalf("invalid board after move %v (%v, %v): %v", i/2, x, y, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package game
+
+import (
+	"math/rand/v2"
+	"sync"
+)
+
+// zobristKeys are the random numbers of Zobrist hashing for a board size: one for each point and color,
+// and one for each player in turn. They are generated with a fixed seed, so hashes are stable between runs
+// and can be stored.
+type zobristKeys struct {
+	stones [][maxColors + 1]uint64
+	turn   [maxColors]uint64
+}
+
+var (
+	zobristMu sync.Mutex
+	zobrist   = make(map[int]*zobristKeys)
+)
+
+func zobristFor(size int) *zobristKeys {
+	zobristMu.Lock()
+	defer zobristMu.Unlock()
+	if z, ok := zobrist[size]; ok {
+		return z
+	}
+	r := rand.New(rand.NewPCG(uint64(size), 0x9e3779b97f4a7c15))
+	z := &zobristKeys{stones: make([][maxColors + 1]uint64, size*size)}
+	for i := range z.stones {
+		for c := BLACK; c <= maxColors; c++ {
+			z.stones[i][c] = r.Uint64()
+		}
+	}
+	for p := 1; p < maxColors; p++ { //black in turn is 0, so the empty board hashes to 0
+		z.turn[p] = r.Uint64()
+	}
+	zobrist[size] = z
+	return z
+}
+
+// zobristHash hashes the stones, by flat index, and the player in turn.
+func zobristHash(stones []pointStateType, next int, size int) uint64 {
+	z := zobristFor(size)
+	h := z.turn[next]
+	for i, s := range stones {
+		if s != FREE {
+			h ^= z.stones[i][s]
+		}
+	}
+	return h
+}
+
+// Hash returns the Zobrist hash of the position: the stones and the player in turn.
+func (g *GoGame) Hash() uint64 {
+	return zobristHash(g.stones(), g.next(), g.board.size)
+}
+
+// stones returns the state of every point by flat index (x*size + y).
+func (g *GoGame) stones() []pointStateType {
+	size := g.board.size
+	stones := make([]pointStateType, size*size)
+	for x := range g.board.field {
+		for y := range g.board.field[x] {
+			stones[x*size+y] = g.board.field[x][y].State
+		}
+	}
+	return stones
+}
+
+// next returns the player in turn, from 0 (black).
+func (g *GoGame) next() int {
+	if g.Colors > 0 {
+		return g.Turn
+	}
+	if g.BlackPlayedLast {
+		return 1
+	}
+	return 0
+}
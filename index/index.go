@@ -0,0 +1,234 @@
+// Package index is a local position database of a game archive, stored in a file.
+//
+// Every position of the main line of every game is indexed by the Zobrist hash of its canonical form,
+// so positions are found whatever the orientation of the board. The stones of each distinct position are
+// stored too, so local patterns are searched in them without replaying the games.
+package index
+
+import (
+	"cmp"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/n-bravo/go-in-go/game"
+)
+
+const version = 2 //version of the file format
+
+// Index is a position index of a game archive, loaded in memory and stored in a file with Save.
+type Index struct {
+	path string
+	data indexData
+}
+
+type indexData struct {
+	Version   int
+	Games     []indexedGame
+	Positions map[uint64][]occurrence //canonical hash to the games reaching the position
+	Boards    map[uint64]string       //canonical hash to the stones of the canonical form, as in GoGame.String
+}
+
+type indexedGame struct {
+	Name   string
+	Record *game.Record
+}
+
+type occurrence struct {
+	Game  int        //position in Games
+	Moves int        //moves played when the position was reached
+	Next  *game.Move //next move in the orientation of the canonical form. nil at the end of the game
+}
+
+// Hit is a game reaching a position or a pattern.
+type Hit struct {
+	Game  string // Name of the game when it was added.
+	Moves int    // Moves played when the position was reached.
+}
+
+// MoveCount is how many times a move was played next.
+type MoveCount struct {
+	Move  game.Move
+	Count int
+}
+
+// Result is the answer to a query.
+type Result struct {
+	Hits      []Hit
+	NextMoves []MoveCount // Moves played next, the most frequent first, in the orientation of the query.
+}
+
+// Open loads the index stored in path, or returns an empty one if the file does not exist yet.
+func Open(path string) (*Index, error) {
+	ix := &Index{path: path, data: indexData{
+		Version:   version,
+		Positions: make(map[uint64][]occurrence),
+		Boards:    make(map[uint64]string),
+	}}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ix, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if err = gob.NewDecoder(f).Decode(&ix.data); err != nil {
+		return nil, fmt.Errorf("error reading index %s: %v", path, err)
+	}
+	if ix.data.Version != version {
+		return nil, fmt.Errorf("unsupported index version %v", ix.data.Version)
+	}
+	return ix, nil
+}
+
+// Save stores the index in its file. The file is replaced at once, so it is never left half written.
+func (ix *Index) Save() error {
+	tmp := ix.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err = gob.NewEncoder(f).Encode(&ix.data); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, ix.path)
+}
+
+// Games returns the number of games indexed.
+func (ix *Index) Games() int {
+	return len(ix.data.Games)
+}
+
+// Add indexes every position of the record.
+func (ix *Index) Add(name string, r *game.Record) error {
+	id := len(ix.data.Games)
+	found := make([]occurrence, 0, len(r.Moves)+1)
+	hashes := make([]uint64, 0, len(r.Moves)+1)
+	boards := make(map[uint64]string)
+	err := r.Replay(func(moves int, g *game.GoGame) error {
+		sym, hash, err := g.Canonical(false)
+		if err != nil {
+			return err
+		}
+		o := occurrence{Game: id, Moves: moves}
+		if moves < len(r.Moves) {
+			next := sym.Moves([]game.Move{r.Moves[moves].Move}, r.Size)[0]
+			o.Next = &next
+		}
+		found = append(found, o)
+		hashes = append(hashes, hash)
+		if _, ok := ix.data.Boards[hash]; !ok {
+			boards[hash] = transformBoard(g.String(), r.Size, sym)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error indexing %s: %v", name, err)
+	}
+	ix.data.Games = append(ix.data.Games, indexedGame{Name: name, Record: r})
+	for i, o := range found {
+		ix.data.Positions[hashes[i]] = append(ix.data.Positions[hashes[i]], o)
+	}
+	maps.Copy(ix.data.Boards, boards)
+	return nil
+}
+
+// AddSGF indexes every game of the SGF collection. Games of a collection are named name#1, name#2...
+func (ix *Index) AddSGF(name string, sgf string) error {
+	roots, err := game.ParseSGF(sgf)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", name, err)
+	}
+	for i, root := range roots {
+		r, err := game.RecordFromSGF(root)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %v", name, err)
+		}
+		gameName := name
+		if len(roots) > 1 {
+			gameName = fmt.Sprintf("%s#%v", name, i+1)
+		}
+		if err = ix.Add(gameName, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddDir indexes every .sgf file in the directory and its subdirectories. Files that can not be indexed are
+// skipped, and their errors returned together with the number of files indexed.
+func (ix *Index) AddDir(dir string) (int, error) {
+	added := 0
+	errs := make([]error, 0)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".sgf") {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err == nil {
+			err = ix.AddSGF(path, string(content))
+		}
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		added += 1
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return added, errors.Join(errs...)
+}
+
+// Find returns the games that reached the position of g, in any orientation, with the same player in turn.
+func (ix *Index) Find(g *game.GoGame) (Result, error) {
+	sym, hash, err := g.Canonical(false)
+	if err != nil {
+		return Result{}, err
+	}
+	inverse := sym.Inverse()
+	hits := make([]Hit, 0)
+	counts := make(map[game.Move]int)
+	for _, o := range ix.data.Positions[hash] {
+		hits = append(hits, Hit{Game: ix.data.Games[o.Game].Name, Moves: o.Moves})
+		if o.Next != nil {
+			counts[inverse.Moves([]game.Move{*o.Next}, g.Size())[0]] += 1
+		}
+	}
+	return Result{Hits: hits, NextMoves: sortedCounts(counts)}, nil
+}
+
+func sortedCounts(counts map[game.Move]int) []MoveCount {
+	moves := make([]MoveCount, 0, len(counts))
+	for m, c := range counts {
+		moves = append(moves, MoveCount{Move: m, Count: c})
+	}
+	slices.SortFunc(moves, func(a, b MoveCount) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		if a.Move.Pass != b.Move.Pass {
+			if a.Move.Pass {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Or(a.Move.X-b.Move.X, a.Move.Y-b.Move.Y)
+	})
+	return moves
+}
@@ -0,0 +1,95 @@
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/n-bravo/go-in-go/game"
+	"github.com/stretchr/testify/assert"
+)
+
+// the second game is the first one rotated 90 degrees
+const (
+	game1 = "(;SZ[5];B[cc];W[ba];B[bb])"
+	game2 = "(;SZ[5];B[cc];W[eb];B[db])"
+	game3 = "(;SZ[5];B[aa];W[ee])"
+)
+
+func newTestIndex(t *testing.T) *Index {
+	dir := t.TempDir()
+	for name, sgf := range map[string]string{"1.sgf": game1, "2.SGF": game2, "3.sgf": game3, "bad.sgf": "(;B[zz]", "notes.txt": "(;)"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(sgf), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ix, err := Open(filepath.Join(dir, "archive.idx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	added, err := ix.AddDir(dir)
+	assert.Equal(t, 3, added)
+	assert.ErrorContains(t, err, "bad.sgf")
+	return ix
+}
+
+func TestFind(t *testing.T) {
+	assert := assert.New(t)
+	ix := newTestIndex(t)
+	assert.Equal(3, ix.Games())
+	assert.NoError(ix.Save())
+	ix, err := Open(ix.path)
+	assert.NoError(err)
+	assert.Equal(3, ix.Games())
+
+	g, _ := game.NewGame(5)
+	assert.NoError(g.Play(2, 2, true))
+	assert.NoError(g.Play(0, 1, false))
+	r, err := ix.Find(g)
+	assert.NoError(err)
+	assert.ElementsMatch([]Hit{{Game: filepath.Join(filepath.Dir(ix.path), "1.sgf"), Moves: 2}, {Game: filepath.Join(filepath.Dir(ix.path), "2.SGF"), Moves: 2}}, r.Hits)
+	assert.Equal([]MoveCount{{Move: game.Move{X: 1, Y: 1}, Count: 2}}, r.NextMoves)
+
+	rotated, _ := g.Transform(game.Rotate180)
+	r, err = ix.Find(rotated)
+	assert.NoError(err)
+	assert.Len(r.Hits, 2)
+	assert.Equal([]MoveCount{{Move: game.Move{X: 3, Y: 3}, Count: 2}}, r.NextMoves)
+
+	empty, _ := game.NewGame(5)
+	r, _ = ix.Find(empty)
+	assert.Len(r.Hits, 3)
+	assert.NoError(g.Play(4, 4, true))
+	r, _ = ix.Find(g)
+	assert.Empty(r.Hits)
+	assert.Empty(r.NextMoves)
+}
+
+func TestFindPattern(t *testing.T) {
+	assert := assert.New(t)
+	ix := newTestIndex(t)
+	assert.Len(ix.data.Boards, 6) //the positions of the rotated games are stored once
+	assert.NoError(ix.Save())
+	ix, err := Open(ix.path)
+	assert.NoError(err)
+	r, err := ix.FindPattern(Pattern{Rows: []string{"?W", "**"}, Corner: true})
+	assert.NoError(err)
+	assert.Len(r.Hits, 2)
+	for _, h := range r.Hits {
+		assert.Equal(2, h.Moves)
+	}
+	assert.Equal([]MoveCount{{Move: game.Move{X: 1, Y: 1}, Count: 2}}, r.NextMoves)
+
+	r, err = ix.FindPattern(Pattern{Rows: []string{"*B*"}})
+	assert.NoError(err)
+	assert.Len(r.Hits, 2) //the third game only has black stones in the corner
+	r, err = ix.FindPattern(Pattern{Rows: []string{"B"}, Corner: true})
+	assert.NoError(err)
+	assert.Equal([]Hit{{Game: r.Hits[0].Game, Moves: 1}}, r.Hits)
+	assert.Equal("3.sgf", filepath.Base(r.Hits[0].Game))
+
+	_, err = ix.FindPattern(Pattern{Rows: []string{"B", "WW"}})
+	assert.Error(err)
+	_, err = ix.FindPattern(Pattern{Rows: []string{"X"}})
+	assert.Error(err)
+}
@@ -0,0 +1,124 @@
+package index
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/n-bravo/go-in-go/game"
+)
+
+// Pattern is a partial position, given by rows of the same length with the characters of GoGame.String
+// ('B', 'W' and '*' for an empty point) and '?' for points that can be anything.
+// The pattern is searched in any orientation of the board.
+type Pattern struct {
+	Rows   []string
+	Corner bool // If true, the pattern must be in a corner of the board, with its first row and column on the edges.
+}
+
+func (p Pattern) validate() error {
+	if len(p.Rows) == 0 || len(p.Rows[0]) == 0 {
+		return fmt.Errorf("empty pattern")
+	}
+	for _, row := range p.Rows {
+		if len(row) != len(p.Rows[0]) {
+			return fmt.Errorf("all the rows of the pattern must have the same length")
+		}
+		for _, c := range row {
+			if c != 'B' && c != 'W' && c != '*' && c != '?' {
+				return fmt.Errorf("invalid character %q in pattern", c)
+			}
+		}
+	}
+	return nil
+}
+
+// matches returns the positions of the top left corner of the pattern in the board, a flat string like
+// GoGame.String.
+func (p Pattern) matches(board string, size int) []game.Coord {
+	h, w := len(p.Rows), len(p.Rows[0])
+	last := game.Coord{X: size - h, Y: size - w}
+	if p.Corner {
+		last = game.Coord{}
+	}
+	found := make([]game.Coord, 0)
+	for ox := 0; ox <= last.X; ox++ {
+		for oy := 0; oy <= last.Y; oy++ {
+			if p.matchesAt(board, size, ox, oy) {
+				found = append(found, game.Coord{X: ox, Y: oy})
+			}
+		}
+	}
+	return found
+}
+
+func (p Pattern) matchesAt(board string, size int, ox, oy int) bool {
+	for x, row := range p.Rows {
+		for y := 0; y < len(row); y++ {
+			if row[y] != '?' && row[y] != board[(ox+x)*size+oy+y] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// FindPattern returns the games where the pattern appears, with the first position where it was found, and
+// the moves played next inside the area of the pattern, in coordinates of the pattern from its top left corner.
+// The pattern is searched once in each distinct position of the index, which is then looked up by its hash.
+func (ix *Index) FindPattern(p Pattern) (Result, error) {
+	if err := p.validate(); err != nil {
+		return Result{}, err
+	}
+	first := make(map[int]int) //game to the first position with the pattern
+	counts := make(map[game.Move]int)
+	for hash, board := range ix.data.Boards {
+		found := ix.data.Positions[hash]
+		size := ix.data.Games[found[0].Game].Record.Size
+		if len(p.Rows) > size || len(p.Rows[0]) > size {
+			continue
+		}
+		next := make([]map[game.Move]bool, len(found)) //moves inside the pattern, counted once per position
+		for _, s := range game.Symmetries {
+			transformed := transformBoard(board, size, s)
+			for _, at := range p.matches(transformed, size) {
+				for k, o := range found {
+					if m, ok := first[o.Game]; !ok || o.Moves < m {
+						first[o.Game] = o.Moves
+					}
+					if o.Next == nil || o.Next.Pass {
+						continue
+					}
+					c := s.Coord(game.Coord{X: o.Next.X, Y: o.Next.Y}, size)
+					c.X, c.Y = c.X-at.X, c.Y-at.Y
+					if c.X >= 0 && c.X < len(p.Rows) && c.Y >= 0 && c.Y < len(p.Rows[0]) {
+						if next[k] == nil {
+							next[k] = make(map[game.Move]bool)
+						}
+						next[k][game.Move{X: c.X, Y: c.Y}] = true
+					}
+				}
+			}
+		}
+		for _, moves := range next {
+			for m := range moves {
+				counts[m] += 1
+			}
+		}
+	}
+	hits := make([]Hit, 0, len(first))
+	for _, id := range slices.Sorted(maps.Keys(first)) {
+		hits = append(hits, Hit{Game: ix.data.Games[id].Name, Moves: first[id]})
+	}
+	return Result{Hits: hits, NextMoves: sortedCounts(counts)}, nil
+}
+
+// transformBoard applies the symmetry to a board in the format of GoGame.String.
+func transformBoard(board string, size int, s game.Symmetry) string {
+	out := make([]byte, len(board))
+	for i := range board {
+		c := s.Coord(game.Coord{X: i / size, Y: i % size}, size)
+		out[c.X*size+c.Y] = board[i]
+	}
+	return string(out)
+}
@@ -1,19 +1,34 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"net/http"
+	"runtime"
+	"strings"
+	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/n-bravo/go-in-go/game"
 	"github.com/n-bravo/go-in-go/server"
 )
 
 func main() {
+	engine := flag.String("gtp-engine", "", "command line of a GTP engine to play against, e.g. \"gnugo --mode gtp\"")
+	engineTimeout := flag.Duration("gtp-timeout", 30*time.Second, "max time the GTP engine has to answer each command")
+	playouts := flag.Int("mcts-playouts", 3000, "playouts per move of the built-in computer opponent")
+	thinkTime := flag.Duration("mcts-time", 0, "thinking time per move of the built-in computer opponent. Overrides -mcts-playouts")
+	flag.Parse()
+	server.Manager.SetComputer(game.MCTSOptions{Playouts: *playouts, Time: *thinkTime, Threads: runtime.NumCPU(), Komi: 7.5})
+	if cmd := strings.Fields(*engine); len(cmd) > 0 {
+		server.Manager.SetEngine(*engineTimeout, cmd[0], cmd[1:]...)
+	}
 	webSocketHandler := server.WebSocketHandler{
 		Upgrader: websocket.Upgrader{},
 		Origins:  []string{"http://localhost:5173", "http://127.0.0.1:5173"},
 	}
 	http.Handle("/", webSocketHandler)
+	http.Handle("GET /diagram/{id}", server.DiagramHandler{})
 	log.Print("Starting server...")
 	log.Fatal(http.ListenAndServe(":3000", nil))
 }
@@ -0,0 +1,83 @@
+package server
+
+import "github.com/n-bravo/go-in-go/game"
+
+// bot is an automatic opponent for a botSession.
+// It is informed of every move played in the session and asked to generate its own moves.
+type bot interface {
+	play(x, y int, black bool) error
+	pass(black bool) error
+	genmove(black bool) (botMove, error)
+	close() error
+}
+
+type botMove struct {
+	x, y   int
+	pass   bool
+	resign bool
+}
+
+// mctsBot is the built-in computer opponent. It searches over the same game of the session,
+// so it does not need to be informed of the moves.
+type mctsBot struct {
+	g    *game.GoGame
+	opts game.MCTSOptions
+}
+
+func newMctsBot(g *game.GoGame, opts game.MCTSOptions) *mctsBot {
+	return &mctsBot{g: g, opts: opts}
+}
+
+func (b *mctsBot) play(x, y int, black bool) error {
+	return nil
+}
+
+func (b *mctsBot) pass(black bool) error {
+	return nil
+}
+
+func (b *mctsBot) genmove(black bool) (botMove, error) {
+	m, err := game.GenMove(b.g, black, b.opts)
+	if err != nil {
+		return botMove{}, err
+	}
+	return botMove{x: m.X, y: m.Y, pass: m.Pass}, nil
+}
+
+func (b *mctsBot) close() error {
+	return nil
+}
+
+// Names of the beginner levels of the built-in computer opponent in HandshakeSessionMessage.Level
+var botLevels = map[string]game.BotLevel{
+	"random":  game.RandomLegal,
+	"capture": game.CaptureFirst,
+	"atari":   game.AtariAware,
+	"safe":    game.AvoidSelfAtari,
+}
+
+// levelBot is a rule-based computer opponent for beginners.
+type levelBot struct {
+	g     *game.GoGame
+	level game.BotLevel
+}
+
+func (b *levelBot) play(x, y int, black bool) error {
+	return nil
+}
+
+func (b *levelBot) pass(black bool) error {
+	return nil
+}
+
+func (b *levelBot) genmove(black bool) (botMove, error) {
+	m, err := game.GenMoveLevel(b.g, black, b.level)
+	if err != nil {
+		return botMove{}, err
+	}
+	return botMove{x: m.X, y: m.Y, pass: m.Pass}, nil
+}
+
+func (b *levelBot) close() error {
+	return nil
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/n-bravo/go-in-go/diagram"
+)
+
+// maxDiagramWidth is the biggest image served, enough for newsletters and chats. The endpoint is public and
+// every request draws the image again.
+const maxDiagramWidth = 1200
+
+// DiagramHandler serves the image of the current position of a session, with the last move marked.
+// It must be registered with a pattern with the {id} wildcard, like "GET /diagram/{id}".
+// The query parameters are:
+//
+//	format       "svg" (default), "png", or "text" for the board drawn with letters
+//	width        width and height of the image in pixels, up to 1200
+//	coordinates  "true" to draw the column letters and row numbers
+type DiagramHandler struct{}
+
+func (DiagramHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g, err := Manager.Position(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	q := r.URL.Query()
+	opts := diagram.Options{LastMove: true}
+	opts.Coordinates, _ = strconv.ParseBool(q.Get("coordinates"))
+	if v := q.Get("width"); v != "" {
+		if opts.Width, err = strconv.Atoi(v); err != nil || opts.Width <= 0 || opts.Width > maxDiagramWidth {
+			http.Error(w, "invalid width "+strconv.Quote(v), http.StatusBadRequest)
+			return
+		}
+	}
+	var buf bytes.Buffer
+	switch q.Get("format") {
+	case "", "svg":
+		w.Header().Set("Content-Type", "image/svg+xml")
+		err = diagram.SVG(&buf, g, opts)
+	case "png":
+		w.Header().Set("Content-Type", "image/png")
+		err = diagram.PNG(&buf, g, opts)
+	case "text":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		buf.WriteString(g.Text() + "\n")
+	default:
+		http.Error(w, "invalid format "+strconv.Quote(q.Get("format")), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("error drawing diagram: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/n-bravo/go-in-go/game"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagramHandler(t *testing.T) {
+	assert := assert.New(t)
+	g, _ := game.NewGame(9)
+	assert.NoError(g.Play(4, 4, true))
+	s := &offlineSession{id: "diagram-test", g: g, m: Manager}
+	Manager.mu.Lock()
+	Manager.sessions[s] = true
+	Manager.mu.Unlock()
+	defer func() {
+		Manager.mu.Lock()
+		delete(Manager.sessions, s)
+		Manager.mu.Unlock()
+	}()
+	mux := http.NewServeMux()
+	mux.Handle("GET /diagram/{id}", DiagramHandler{})
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		return w
+	}
+	w := get("/diagram/diagram-test?coordinates=true")
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Equal("image/svg+xml", w.Header().Get("Content-Type"))
+	assert.True(strings.HasPrefix(w.Body.String(), "<svg "))
+	assert.Contains(w.Body.String(), ">J</text>")
+
+	w = get("/diagram/diagram-test?format=png&width=100")
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Equal("image/png", w.Header().Get("Content-Type"))
+	assert.True(strings.HasPrefix(w.Body.String(), "\x89PNG"))
+
+	w = get("/diagram/diagram-test?format=text")
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Contains(w.Body.String(), "5 . . . .(B). . . . 5")
+
+	assert.Equal(http.StatusNotFound, get("/diagram/unknown").Code)
+	assert.Equal(http.StatusBadRequest, get("/diagram/diagram-test?format=gif").Code)
+	assert.Equal(http.StatusBadRequest, get("/diagram/diagram-test?width=-3").Code)
+	assert.Equal(http.StatusBadRequest, get("/diagram/diagram-test?format=png&width=4000").Code)
+}
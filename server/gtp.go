@@ -0,0 +1,202 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GTP column letters. The letter I is skipped by the protocol.
+const gtpColumns = "ABCDEFGHJKLMNOPQRSTUVWXYZ"
+
+type gtpResponse struct {
+	ok   bool
+	text string
+}
+
+// gtpEngine is an external engine process spoken to with the Go Text Protocol
+// through its stdin and stdout.
+type gtpEngine struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	responses chan gtpResponse //closed when the engine stdout is closed
+	done      chan struct{}    //closed when the engine is killed
+	timeout   time.Duration    //max time to wait for each response
+	size      int
+	dead      bool
+}
+
+func newGtpEngine(size int, timeout time.Duration, name string, args ...string) (*gtpEngine, error) {
+	if len(gtpColumns) < size {
+		return nil, fmt.Errorf("invalid board size %v for gtp engine", size)
+	}
+	cmd := exec.Command(name, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("error starting gtp engine: %v", err)
+	}
+	e := &gtpEngine{
+		cmd:       cmd,
+		stdin:     stdin,
+		responses: make(chan gtpResponse),
+		done:      make(chan struct{}),
+		timeout:   timeout,
+		size:      size,
+	}
+	go e.readLoop(stdout)
+	if _, err = e.send("boardsize " + strconv.Itoa(size)); err != nil {
+		e.close()
+		return nil, err
+	}
+	if _, err = e.send("clear_board"); err != nil {
+		e.close()
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *gtpEngine) readLoop(r io.Reader) {
+	defer close(e.responses)
+	scanner := bufio.NewScanner(r)
+	var resp *gtpResponse
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if resp == nil {
+			if line == "" {
+				continue
+			}
+			if line[0] != '=' && line[0] != '?' {
+				continue //debug output, not a response
+			}
+			//drop the status character and the optional command id
+			text := strings.TrimLeft(line[1:], "0123456789")
+			resp = &gtpResponse{ok: line[0] == '='}
+			lines = []string{strings.TrimSpace(text)}
+			continue
+		}
+		if line == "" { //an empty line finishes the response
+			resp.text = strings.TrimSpace(strings.Join(lines, "\n"))
+			select {
+			case e.responses <- *resp:
+			case <-e.done:
+				return
+			}
+			resp = nil
+			continue
+		}
+		lines = append(lines, line)
+	}
+}
+
+// send writes a command to the engine and waits for its response.
+// Any failure other than an error response from the engine leaves the engine unusable.
+func (e *gtpEngine) send(command string) (string, error) {
+	if e.dead {
+		return "", fmt.Errorf("gtp engine is not running")
+	}
+	if _, err := io.WriteString(e.stdin, command+"\n"); err != nil {
+		e.kill()
+		return "", fmt.Errorf("gtp engine crashed: %v", err)
+	}
+	select {
+	case resp, ok := <-e.responses:
+		if !ok {
+			e.kill()
+			return "", fmt.Errorf("gtp engine crashed while processing %q", command)
+		}
+		if !resp.ok {
+			return "", fmt.Errorf("gtp engine refused %q: %s", command, resp.text)
+		}
+		return resp.text, nil
+	case <-time.After(e.timeout):
+		e.kill()
+		return "", fmt.Errorf("gtp engine timed out after %v processing %q", e.timeout, command)
+	}
+}
+
+func (e *gtpEngine) play(x, y int, black bool) error {
+	_, err := e.send(fmt.Sprintf("play %s %s", gtpColor(black), gtpVertex(x, y, e.size)))
+	return err
+}
+
+func (e *gtpEngine) pass(black bool) error {
+	_, err := e.send(fmt.Sprintf("play %s pass", gtpColor(black)))
+	return err
+}
+
+func (e *gtpEngine) genmove(black bool) (botMove, error) {
+	resp, err := e.send("genmove " + gtpColor(black))
+	if err != nil {
+		return botMove{}, err
+	}
+	switch strings.ToLower(resp) {
+	case "pass":
+		return botMove{pass: true}, nil
+	case "resign":
+		return botMove{resign: true}, nil
+	}
+	x, y, err := parseGtpVertex(resp, e.size)
+	if err != nil {
+		return botMove{}, err
+	}
+	return botMove{x: x, y: y}, nil
+}
+
+func (e *gtpEngine) close() error {
+	if e.dead {
+		return nil
+	}
+	e.send("quit")
+	e.kill()
+	return nil
+}
+
+func (e *gtpEngine) kill() {
+	if e.dead {
+		return
+	}
+	e.dead = true
+	close(e.done)
+	e.stdin.Close()
+	e.cmd.Process.Kill()
+	go e.cmd.Wait()
+}
+
+func gtpColor(black bool) string {
+	if black {
+		return "B"
+	}
+	return "W"
+}
+
+// gtpVertex converts a board position to GTP notation.
+// x is the row counted from the top and y the column counted from the left,
+// while GTP counts rows from the bottom, starting in 1.
+func gtpVertex(x, y, size int) string {
+	return string(gtpColumns[y]) + strconv.Itoa(size-x)
+}
+
+func parseGtpVertex(v string, size int) (int, int, error) {
+	v = strings.ToUpper(strings.TrimSpace(v))
+	if len(v) < 2 {
+		return 0, 0, fmt.Errorf("invalid gtp vertex %q", v)
+	}
+	y := strings.IndexByte(gtpColumns, v[0])
+	row, err := strconv.Atoi(v[1:])
+	if y < 0 || y >= size || err != nil || row < 1 || row > size {
+		return 0, 0, fmt.Errorf("invalid gtp vertex %q", v)
+	}
+	return size - row, y, nil
+}
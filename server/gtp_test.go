@@ -0,0 +1,137 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The test binary doubles as a stub GTP engine when this variable is set.
+// Its value selects how the stub behaves on genmove: "play", "crash" or "hang".
+const stubEngineEnv = "GO_IN_GO_STUB_ENGINE"
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(stubEngineEnv); mode != "" {
+		runStubEngine(mode)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runStubEngine answers GTP commands from stdin, generating moves in the first free vertex
+func runStubEngine(mode string) {
+	size := 19
+	taken := make(map[string]bool)
+	in := bufio.NewScanner(os.Stdin)
+	for in.Scan() {
+		fields := strings.Fields(in.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		resp := "="
+		switch fields[0] {
+		case "boardsize":
+			fmt.Sscan(fields[1], &size)
+		case "play":
+			taken[strings.ToUpper(fields[2])] = true
+		case "genmove":
+			switch mode {
+			case "crash":
+				os.Exit(1)
+			case "hang":
+				time.Sleep(time.Hour)
+			}
+			resp = "= pass"
+			for x := 0; x < size && resp == "= pass"; x++ {
+				for y := 0; y < size; y++ {
+					if v := gtpVertex(x, y, size); !taken[v] {
+						taken[v] = true
+						resp = "= " + v
+						break
+					}
+				}
+			}
+		case "quit":
+			fmt.Print("=\n\n")
+			return
+		case "clear_board":
+		default:
+			resp = "? unknown command"
+		}
+		fmt.Print(resp + "\n\n")
+	}
+}
+
+func startStubEngine(t *testing.T, mode string, size int) *gtpEngine {
+	t.Setenv(stubEngineEnv, mode)
+	e, err := newGtpEngine(size, time.Second, os.Args[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { e.close() })
+	return e
+}
+
+func TestGtpVertex(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("A19", gtpVertex(0, 0, 19))
+	assert.Equal("T1", gtpVertex(18, 18, 19))
+	assert.Equal("J9", gtpVertex(0, 8, 9))
+	x, y, err := parseGtpVertex("j9", 9)
+	assert.NoError(err)
+	assert.Equal(0, x)
+	assert.Equal(8, y)
+	x, y, err = parseGtpVertex("A1", 5)
+	assert.NoError(err)
+	assert.Equal(4, x)
+	assert.Equal(0, y)
+	_, _, err = parseGtpVertex("I3", 9)
+	assert.Error(err)
+	_, _, err = parseGtpVertex("F1", 5)
+	assert.Error(err)
+	_, _, err = parseGtpVertex("A6", 5)
+	assert.Error(err)
+	_, _, err = parseGtpVertex("A", 5)
+	assert.Error(err)
+}
+
+func TestGtpEngineGenmove(t *testing.T) {
+	assert := assert.New(t)
+	e := startStubEngine(t, "play", 5)
+	assert.NoError(e.play(0, 0, true))
+	mv, err := e.genmove(false)
+	assert.NoError(err)
+	assert.Equal(botMove{x: 0, y: 1}, mv)
+	assert.NoError(e.pass(true))
+	_, err = e.send("unknown_command")
+	assert.Error(err)
+	assert.False(e.dead)
+}
+
+func TestGtpEngineCrash(t *testing.T) {
+	assert := assert.New(t)
+	e := startStubEngine(t, "crash", 5)
+	_, err := e.genmove(true)
+	assert.Error(err)
+	assert.True(e.dead)
+	assert.Error(e.play(0, 0, true))
+}
+
+func TestGtpEngineTimeout(t *testing.T) {
+	assert := assert.New(t)
+	e := startStubEngine(t, "hang", 5)
+	e.timeout = 100 * time.Millisecond
+	_, err := e.genmove(true)
+	assert.ErrorContains(err, "timed out")
+	assert.True(e.dead)
+}
+
+func TestGtpEngineNotFound(t *testing.T) {
+	_, err := newGtpEngine(5, time.Second, "./no-such-gtp-engine")
+	assert.Error(t, err)
+}
@@ -1,29 +1,77 @@
 package server
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"runtime"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/n-bravo/go-in-go/game"
+)
+
+const (
+	defaultKomi      = 7.5
+	estimatePlayouts = 100 //playouts of the estimates requested by clients
 )
 
 type SessionManager struct {
-	mu       sync.Mutex
-	sessions map[session]bool
+	mu              sync.Mutex
+	sessions        map[session]bool
+	engineCmd       []string      //command line of the GTP engine. Empty if no engine is configured
+	engineTimeout   time.Duration //max time the GTP engine has to answer each command
+	computerOptions game.MCTSOptions
 }
 
 func NewSessionManager() *SessionManager {
 	uuid.EnableRandPool()
 	return &SessionManager{
 		sessions: make(map[session]bool),
+		computerOptions: game.MCTSOptions{
+			Playouts: 3000,
+			Threads:  runtime.NumCPU(),
+			Komi:     defaultKomi,
+		},
+	}
+}
+
+// SetEngine configures the GTP engine executable started for each session against an engine.
+func (m *SessionManager) SetEngine(timeout time.Duration, name string, args ...string) {
+	m.engineCmd = append([]string{name}, args...)
+	m.engineTimeout = timeout
+}
+
+// SetComputer configures the search of the built-in computer opponent.
+func (m *SessionManager) SetComputer(opts game.MCTSOptions) {
+	m.computerOptions = opts
+}
+
+func (m *SessionManager) EngineAvailable() bool {
+	return len(m.engineCmd) > 0
+}
+
+func (m *SessionManager) newEngine(size int) (*gtpEngine, error) {
+	if !m.EngineAvailable() {
+		return nil, fmt.Errorf("no gtp engine configured")
 	}
+	return newGtpEngine(size, m.engineTimeout, m.engineCmd[0], m.engineCmd[1:]...)
 }
 
-func (m *SessionManager) NewSession(c *websocket.Conn, size int, online bool) {
-	s, err := newSession(c, size, online, m)
+func (m *SessionManager) NewSession(c *websocket.Conn, h HandshakeSessionMessage) {
+	s, err := newSession(c, h, m)
 	if err != nil {
-		log.Fatal(err)
+		msg := fmt.Sprintf("error creating new session: %v", err)
+		log.Println(msg)
+		code := 500
+		if errors.As(err, new(handshakeError)) {
+			code = 401
+		}
+		c.WriteJSON(&ResponseMessage{Code: code, Message: msg})
+		c.Close()
+		return
 	}
 
 	go func() {
@@ -52,9 +100,26 @@ func (m *SessionManager) JoinSession(id string, c *websocket.Conn) {
 }
 
 func (m *SessionManager) CloseSession(s session) {
-	go func(){
+	go func() {
 		m.mu.Lock()
 		delete(m.sessions, s)
 		m.mu.Unlock()
 	}()
 }
+
+// Position returns a copy of the current game of the session with the given id.
+func (m *SessionManager) Position(id string) (*game.GoGame, error) {
+	m.mu.Lock()
+	var found session
+	for s := range m.sessions {
+		if s.getId() == id {
+			found = s
+			break
+		}
+	}
+	m.mu.Unlock()
+	if found == nil {
+		return nil, fmt.Errorf("session id %s not found", id)
+	}
+	return found.position()
+}
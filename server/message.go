@@ -4,9 +4,18 @@ package server
 
 // Initial from client to server to open a websocket connection
 type HandshakeSessionMessage struct {
-	SessionId string `json:"sessionId"` // ID of the session to join. Empty string if want to create a new session
-	Size      int    `json:"size"`      // Board size of the new session. Only 5 and 19 supported currently. Ignored if SessionId is not empty.
-	Online    bool   `json:"online"`    // 'true' if want to create a new online session. 'false' otherwise. Ignored if SessionId is not empty.
+	SessionId     string `json:"sessionId"`     // ID of the session to join. Empty string if want to create a new session
+	Size          int    `json:"size"`          // Board size of the new session. Only 5 and 19 supported currently. Ignored if SessionId is not empty.
+	Online        bool   `json:"online"`        // 'true' if want to create a new online session. 'false' otherwise. Ignored if SessionId is not empty.
+	Engine        bool   `json:"engine"`        // 'true' if want to play an offline session against the GTP engine configured in the server. Ignored if SessionId is not empty or Online is true.
+	Computer      bool   `json:"computer"`      // 'true' if want to play an offline session against the built-in computer opponent. Ignored if SessionId is not empty, Online is true or Engine is true.
+	Level         string `json:"level"`         // Level of the built-in computer opponent: "random", "capture", "atari" or "safe" for beginners, empty for the full strength search. Only used if Computer is true.
+	CaptureTarget int    `json:"captureTarget"` // If positive, the new session is Capture Go (Atari Go): the first player capturing this many stones wins. Ignored if SessionId is not empty or Problem is not empty.
+	Seats         int    `json:"seats"`         // Number of players of the new online session, 2 if omitted. 4 for rengo (pair Go). Up to 10. Only used if Online is true.
+	Rotation      []int  `json:"rotation"`      // Order in which the seats play, by seat number. The colors take turns in order, so with two colors seats in even positions play black and in odd positions white. In joining order if omitted. Only used if Online is true.
+	Colors        int    `json:"colors"`        // If 3 or more, the new online session is multi-color Go, where this many players take turns: black, white, red, green and yellow. Up to 5. Only used if Online is true.
+	Phantom       bool   `json:"phantom"`       // 'true' if the new online session is Phantom Go: each player only sees their own stones, and the opponent stones found when trying to play on them. Only used if Online is true.
+	Problem       string `json:"problem"`       // SGF of a life-and-death problem, with setup stones and the solution tree, in a board up to 25 x 25. If not empty, creates a problem session and Size, Online, Engine and Computer are ignored.
 }
 
 // Response from server to client after a HandshakeSessionMessage is process.
@@ -17,11 +26,14 @@ type HandshakeSessionMessage struct {
 // * = empty intersection
 // B = intersection taken by black stones
 // W = intersection taken by white stones
+// R, G, Y = intersection taken by red, green or yellow stones, in multi-color Go
 type NewSessionResponseMessage struct {
 	SessionId string `json:"sessionId"` // ID of the new session or the session joined.
 	Online    bool   `json:"online"`    // true if the session is online. false otherwise.
 	BlackSide bool   `json:"blackSide"` // true if the client is assigned to black side. false if assigned to white side.
 	BStatus   string `json:"bStatus"`   // Board status when creating or joining the session.
+	Seat      int    `json:"seat"`      // Seat of the client in online sessions, in joining order from 0. The session creator is seat 0.
+	Color     string `json:"color"`     // Color of the client in online sessions: "black", "white", or "red", "green" and "yellow" in multi-color Go.
 }
 
 // User movement action message for an offline match.
@@ -32,20 +44,52 @@ type OffilePlayerInputMessage struct {
 	Y            int  `json:"y"`            // Y position of the movement
 	Black        bool `json:"black"`        // true if the movement correspond to black side, false otherwise
 	CloseSession bool `json:"closeSession"` // true if want to close the connection, finishing the session. Omit or false otherwise.
+	Estimate     bool `json:"estimate"`     // true if want an EstimateResponseMessage of the current game instead of making a movement.
+}
+
+// User movement action message for a match against an automatic opponent.
+// The client always plays black. After each valid movement the server answers with a ResponseMessage
+// containing the board status after the opponent reply.
+type BotPlayerInputMessage struct {
+	X            int  `json:"x"`            // X position of the movement
+	Y            int  `json:"y"`            // Y position of the movement
+	Pass         bool `json:"pass"`         // true if the player passes instead of placing a stone. X and Y are ignored.
+	CloseSession bool `json:"closeSession"` // true if want to close the connection, finishing the session. Omit or false otherwise.
+}
+
+// User movement action message for a problem session.
+// The client plays the side to move in the problem. After each movement the server answers with a ResponseMessage
+// with the result, and the board status after the response of the problem.
+type ProblemPlayerInputMessage struct {
+	X            int  `json:"x"`            // X position of the movement
+	Y            int  `json:"y"`            // Y position of the movement
+	CloseSession bool `json:"closeSession"` // true if want to close the connection, finishing the session. Omit or false otherwise.
 }
 
 // User movement action message for an online match.
-// The side is assigned according to the connection order. The session creator is black side, and the client joining after is white side.
+// The seat is assigned according to the connection order, and its side by the rotation of the session. By default the session
+// creator is black side, and the client joining after is white side. Only the seat in turn can move, and every seat receives the board status.
+// In Phantom Go, the board status sent to each client only has the stones it can see, and estimates are not available.
 type OnlinePlayerInputMessage struct {
 	X         int  `json:"x"`         // X position of the movement
 	Y         int  `json:"y"`         // Y position of the movement
 	CloseConn bool `json:"closeConn"` // true if want to close the connection. Omit or false otherwise. The session will be still alive as long one client is connected.
-
+	Estimate  bool `json:"estimate"`  // true if want an EstimateResponseMessage of the current game instead of making a movement. Only sent to the requesting client.
 }
 
 // Response from server to client after a new movement from the client
 type ResponseMessage struct {
-	Code    int    `json:"code"`    // HTTP convention (for easy understanding). 200 is a correct move. 401 is a forbidden move (either by wrong turn order or invalid position)
+	Code    int    `json:"code"`    // HTTP convention (for easy understanding). 200 is a correct move. 401 is a forbidden move (either by wrong turn order or invalid position). 500 is a failure of the automatic opponent, closing the session.
 	Message string `json:"message"` // In case Code is not 200, the server will provide a message to explaing why.
 	BStatus string `json:"bStatus"` // Board status after a valid client movement. Same format as NewSessionResponseMessage.BStatus
+	Result  string `json:"result"`  // Only in problem sessions: "correct", "wrong", "off-tree" if the movement is not in the solution (and it is not played), or "open" if the problem goes on. Message is then the comment of the solution tree, if any.
+	Winner  string `json:"winner"`  // Only in Capture Go sessions: "black" or "white" when the movement finishes the game, sent to every player. Empty while the game goes on.
+}
+
+// Response from server to client after an estimate request, telling who is ahead in the current game.
+type EstimateResponseMessage struct {
+	Code      int         `json:"code"`      // Same as ResponseMessage.Code.
+	Message   string      `json:"message"`   // In case Code is not 200, the server will provide a message to explaing why.
+	Ownership [][]float64 `json:"ownership"` // Ownership of each intersection, by rows, from -1 (white) to 1 (black).
+	Score     float64     `json:"score"`     // Estimated score, with komi. Positive if black is ahead, negative if white is ahead.
 }
@@ -38,15 +38,29 @@ func (wsh WebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		if m.SessionId == "" { //create new session
-			if m.Size != 19 && m.Size != 5 {
+			if m.Problem == "" && m.Size != 19 && m.Size != 5 {
 				msg := "error invalid board size"
 				log.Println(msg)
 				c.WriteJSON(&ResponseMessage{Code: 401, Message: msg})
 				c.Close()
 				return
 			}
+			if m.Engine && !m.Online && !Manager.EngineAvailable() {
+				msg := "error no gtp engine available in the server"
+				log.Println(msg)
+				c.WriteJSON(&ResponseMessage{Code: 401, Message: msg})
+				c.Close()
+				return
+			}
+			if _, ok := botLevels[m.Level]; m.Computer && m.Level != "" && !ok {
+				msg := fmt.Sprintf("error invalid computer level %q", m.Level)
+				log.Println(msg)
+				c.WriteJSON(&ResponseMessage{Code: 401, Message: msg})
+				c.Close()
+				return
+			}
 			log.Printf("Creating new session")
-			Manager.NewSession(c, m.Size, m.Online)
+			Manager.NewSession(c, m)
 			return
 		} else {
 			if !Manager.OnlineSessionExists(m.SessionId) {
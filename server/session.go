@@ -1,8 +1,10 @@
 package server
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"sync"
 
 	"github.com/google/uuid"
@@ -15,38 +17,138 @@ type session interface {
 	mainLoop()
 	addPlayer(c *websocket.Conn)
     isOnline() bool
+	position() (*game.GoGame, error)
 	close(con *websocket.Conn) error
 }
 
 type offlineSession struct {
 	id   string
 	conn *websocket.Conn
+	mu   sync.Mutex //blocks the access to the board game g while it changes
 	g    *game.GoGame
 	m    *SessionManager
 }
 
+// botSession is an offline session where the client plays black against an automatic opponent.
+type botSession struct {
+	id   string
+	conn *websocket.Conn
+	mu   sync.Mutex //blocks the access to the board game g while it changes
+	g    *game.GoGame
+	b    bot
+	m    *SessionManager
+}
+
+// problemSession is an offline session where the client solves a life-and-death problem.
+type problemSession struct {
+	id   string
+	conn *websocket.Conn
+	mu   sync.Mutex //blocks the access to the problem p while it changes
+	p    *game.Problem
+	m    *SessionManager
+}
+
 type onlineSession struct {
-	id         string
-	mu         sync.Mutex      //only used to block the access to the board game g
-	con1, con2 *websocket.Conn //con1 is always black, con2 is always white
-	g          *game.GoGame
-	m          *SessionManager
+	id       string
+	mu       sync.Mutex        //blocks the access to the board game g, the seats and the turn
+	seats    []*websocket.Conn //connections by seat, in joining order. nil if the seat is free
+	rotation []int             //seats in playing order. Seats in even positions play black, in odd positions white
+	moves    int               //moves played, the seat in turn is rotation[moves % len(rotation)]
+	g        *game.GoGame
+	phantom  *game.Phantom //only in Phantom Go sessions, playing on g
+	m        *SessionManager
 }
 
-func newSession(c *websocket.Conn, n int, online bool, m *SessionManager) (session, error) {
-	g, err := game.NewGame(n)
+// handshakeError is an invalid handshake message of the client, answered with code 401 instead of 500.
+type handshakeError struct {
+	err error
+}
+
+func (e handshakeError) Error() string {
+	return e.err.Error()
+}
+
+func (e handshakeError) Unwrap() error {
+	return e.err
+}
+
+func newSession(c *websocket.Conn, h HandshakeSessionMessage, m *SessionManager) (session, error) {
+	if h.Problem != "" {
+		p, err := game.NewProblem(h.Problem)
+		if err != nil {
+			return nil, handshakeError{fmt.Errorf("invalid problem: %v", err)}
+		}
+		s := &problemSession{
+			id:   uuid.NewString(),
+			conn: c,
+			p:    p,
+			m:    m,
+		}
+		if err = s.conn.WriteJSON(&NewSessionResponseMessage{SessionId: s.id, Online: false, BlackSide: p.Black, BStatus: p.Game.Rows()}); err != nil {
+			return nil, fmt.Errorf("error when sending new session information to client: %s", err)
+		}
+		go s.mainLoop()
+		return s, nil
+	}
+	var g *game.GoGame
+	var err error
+	if h.Colors > 2 && !h.Online {
+		return nil, fmt.Errorf("multi-color go is only available in online sessions")
+	} else if h.Colors > 2 && h.Phantom {
+		return nil, fmt.Errorf("phantom go is only available for two players")
+	}
+	if h.Colors > 2 {
+		g, err = game.NewMultiColorGame(h.Size, h.Colors)
+	} else if h.CaptureTarget > 0 {
+		g, err = game.NewCaptureGame(h.Size, h.CaptureTarget)
+	} else {
+		g, err = game.NewGame(h.Size)
+	}
 	if err != nil {
 		return nil, err
 	}
-	if online {
+	if h.Online {
+		rotation, err := newRotation(h.Seats, h.Rotation, max(h.Colors, 2))
+		if err != nil {
+			return nil, handshakeError{err}
+		}
 		s := &onlineSession{
+			id:       uuid.NewString(),
+			seats:    make([]*websocket.Conn, len(rotation)),
+			rotation: rotation,
+			g:        g,
+			m:        m,
+		}
+		s.seats[0] = c
+		if h.Phantom {
+			s.phantom = game.NewPhantom(g)
+		}
+		if err = c.WriteJSON(&NewSessionResponseMessage{SessionId: s.id, Online: true, BlackSide: s.player(0) == 0, Seat: 0, Color: game.ColorName(s.player(0)), BStatus: s.view(s.player(0))}); err != nil {
+			return nil, fmt.Errorf("error when sending new session information to client: %s", err)
+		}
+		go s.mainLoop()
+		return s, nil
+	} else if h.Engine || h.Computer {
+		var b bot
+		if h.Engine {
+			b, err = m.newEngine(h.Size)
+			if err != nil {
+				return nil, err
+			}
+		} else if level, ok := botLevels[h.Level]; ok {
+			b = &levelBot{g: g, level: level}
+		} else {
+			b = newMctsBot(g, m.computerOptions)
+		}
+		s := &botSession{
 			id:   uuid.NewString(),
-			con1: c,
-			con2: nil,
+			conn: c,
 			g:    g,
+			b:    b,
 			m:    m,
 		}
-		if err = s.con1.WriteJSON(&NewSessionMessage{SessionId: s.id, Online: true, BlackSide: true}); err != nil {
+		if err = s.conn.WriteJSON(&NewSessionResponseMessage{SessionId: s.id, Online: false, BlackSide: true}); err != nil {
+			b.close()
 			return nil, fmt.Errorf("error when sending new session information to client: %s", err)
 		}
 		go s.mainLoop()
@@ -58,7 +160,7 @@ func newSession(c *websocket.Conn, n int, online bool, m *SessionManager) (sessi
 			g:    g,
 			m:    m,
 		}
-		if err = s.conn.WriteJSON(&NewSessionMessage{SessionId: s.id, Online: false, BlackSide: true}); err != nil {
+		if err = s.conn.WriteJSON(&NewSessionResponseMessage{SessionId: s.id, Online: false, BlackSide: true}); err != nil {
 			return nil, fmt.Errorf("error when sending new session information to client: %s", err)
 		}
 		go s.mainLoop()
@@ -70,6 +172,14 @@ func (s *offlineSession) getId() string {
 	return s.id
 }
 
+func (s *botSession) getId() string {
+	return s.id
+}
+
+func (s *problemSession) getId() string {
+	return s.id
+}
+
 func (s *onlineSession) getId() string {
 	return s.id
 }
@@ -78,32 +188,72 @@ func (s *offlineSession) isOnline() bool {
     return false
 }
 
+func (s *botSession) isOnline() bool {
+	return false
+}
+
+func (s *problemSession) isOnline() bool {
+	return false
+}
+
 func (s *onlineSession) isOnline() bool {
     return true
 }
 
+// position returns a copy of the current game of the session.
+func (s *offlineSession) position() (*game.GoGame, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.g.Clone(), nil
+}
+
+func (s *botSession) position() (*game.GoGame, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.g.Clone(), nil
+}
+
+func (s *problemSession) position() (*game.GoGame, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.p.Game.Clone(), nil
+}
+
+func (s *onlineSession) position() (*game.GoGame, error) {
+	if s.phantom != nil {
+		return nil, fmt.Errorf("the position of Phantom Go sessions is hidden")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.g.Clone(), nil
+}
+
 func (s *offlineSession) addPlayer(c *websocket.Conn) {
 }
 
+func (s *botSession) addPlayer(c *websocket.Conn) {
+}
+
+func (s *problemSession) addPlayer(c *websocket.Conn) {
+}
+
 func (s *onlineSession) addPlayer(c *websocket.Conn) {
-	if s.con1 != nil && s.con2 != nil {
+	s.mu.Lock()
+	seat := slices.Index(s.seats, nil)
+	if seat < 0 {
+		s.mu.Unlock()
 		msg := fmt.Sprintf("error session %s is already full", s.id)
 		log.Print(msg)
 		c.WriteJSON(&ResponseMessage{Code: 401, Message: msg})
 		c.Close()
 		return
 	}
-	if s.con1 == nil {
-		log.Printf("Player 1 joined to session %s", s.id)
-		go s.onlinePlayerLoop(true)
-		s.con1 = c
-        c.WriteJSON(&NewSessionMessage{SessionId: s.id, Online: true, BlackSide: true, BStatus: s.g.String()})
-	} else {
-		log.Printf("Player 2 joined to session %s", s.id)
-		go s.onlinePlayerLoop(false)
-		s.con2 = c
-        c.WriteJSON(&NewSessionMessage{SessionId: s.id, Online: true, BlackSide: false, BStatus: s.g.String()})
-	}
+	log.Printf("Player %v joined to session %s", seat+1, s.id)
+	s.seats[seat] = c
+	status := s.view(s.player(seat))
+	s.mu.Unlock()
+	c.WriteJSON(&NewSessionResponseMessage{SessionId: s.id, Online: true, BlackSide: s.player(seat) == 0, Seat: seat, Color: game.ColorName(s.player(seat)), BStatus: status})
+	go s.onlinePlayerLoop(seat, c)
 }
 
 func (s *offlineSession) mainLoop() {
@@ -123,30 +273,149 @@ func (s *offlineSession) mainLoop() {
 			log.Printf("Client request close session %s", s.id)
 			return
 		}
-		if err = s.g.Play(input.X, input.Y, input.Black); err != nil {
+		if input.Estimate {
+			s.conn.WriteJSON(newEstimateResponse(s.g))
+			continue
+		}
+		s.mu.Lock()
+		err = s.g.Play(input.X, input.Y, input.Black)
+		s.mu.Unlock()
+		if err != nil {
 			msg := fmt.Sprintf("Invalid request from client: %s", err)
 			log.Println(msg)
 			s.conn.WriteJSON(&ResponseMessage{Code: 401, Message: msg})
 			continue
 		}
-		s.conn.WriteJSON(&ResponseMessage{Code: 200, Message: ""})
+		winner, msg := captureWinner(s.g)
+		s.conn.WriteJSON(&ResponseMessage{Code: 200, Message: msg, Winner: winner})
 	}
 }
 
-func (s *onlineSession) mainLoop() {
-	go s.onlinePlayerLoop(true)
+func (s *botSession) mainLoop() {
+	defer s.close(s.conn)
+	defer s.m.CloseSession(s)
+	for {
+		var err error
+		var input BotPlayerInputMessage
+		if err = s.conn.ReadJSON(&input); err != nil {
+			if websocket.IsCloseError(err) || websocket.IsUnexpectedCloseError(err) {
+				return
+			}
+			log.Printf("Error when reading input from client from session %s: %v", s.id, err)
+			continue
+		}
+		if input.CloseSession {
+			log.Printf("Client request close session %s", s.id)
+			return
+		}
+		s.mu.Lock()
+		if input.Pass {
+			err = s.g.Pass(true)
+		} else {
+			err = s.g.Play(input.X, input.Y, true)
+		}
+		s.mu.Unlock()
+		if err != nil {
+			msg := fmt.Sprintf("Invalid request from client: %s", err)
+			log.Println(msg)
+			s.conn.WriteJSON(&ResponseMessage{Code: 401, Message: msg})
+			continue
+		}
+		if winner, msg := captureWinner(s.g); winner != "" {
+			s.conn.WriteJSON(&ResponseMessage{Code: 200, Message: msg, BStatus: s.g.Rows(), Winner: winner})
+			return
+		}
+		if input.Pass {
+			err = s.b.pass(true)
+		} else {
+			err = s.b.play(input.X, input.Y, true)
+		}
+		if err != nil {
+			s.botFailure(err)
+			return
+		}
+		mv, err := s.b.genmove(false)
+		if err != nil {
+			s.botFailure(err)
+			return
+		}
+		switch {
+		case mv.resign:
+			s.conn.WriteJSON(&ResponseMessage{Code: 200, Message: "opponent resigned", BStatus: s.g.Rows()})
+			return
+		case mv.pass:
+			s.mu.Lock()
+			s.g.Pass(false)
+			s.mu.Unlock()
+			if input.Pass {
+				s.conn.WriteJSON(&ResponseMessage{Code: 200, Message: "both players passed, game finished", BStatus: s.g.Rows()})
+				return
+			}
+			s.conn.WriteJSON(&ResponseMessage{Code: 200, Message: "opponent passed", BStatus: s.g.Rows()})
+		default:
+			s.mu.Lock()
+			err = s.g.Play(mv.x, mv.y, false)
+			s.mu.Unlock()
+			if err != nil {
+				s.botFailure(fmt.Errorf("opponent played an invalid move: %v", err))
+				return
+			}
+			winner, msg := captureWinner(s.g)
+			s.conn.WriteJSON(&ResponseMessage{Code: 200, Message: msg, BStatus: s.g.Rows(), Winner: winner})
+			if winner != "" {
+				return
+			}
+		}
+	}
 }
 
-func (s *onlineSession) onlinePlayerLoop(black bool) {
-	var con *websocket.Conn
-	var pname byte
-	if black {
-		con = s.con1
-		pname = '1'
-	} else {
-		con = s.con2
-		pname = '2'
+func (s *botSession) botFailure(err error) {
+	msg := fmt.Sprintf("Error in session %s opponent: %v", s.id, err)
+	log.Println(msg)
+	s.conn.WriteJSON(&ResponseMessage{Code: 500, Message: msg})
+}
+
+func (s *problemSession) mainLoop() {
+	defer s.close(s.conn)
+	defer s.m.CloseSession(s)
+	for {
+		var err error
+		var input ProblemPlayerInputMessage
+		if err = s.conn.ReadJSON(&input); err != nil {
+			if websocket.IsCloseError(err) || websocket.IsUnexpectedCloseError(err) {
+				return
+			}
+			log.Printf("Error when reading input from client from session %s: %v", s.id, err)
+			continue
+		}
+		if input.CloseSession {
+			log.Printf("Client request close session %s", s.id)
+			return
+		}
+		s.mu.Lock()
+		result, _, err := s.p.Play(input.X, input.Y)
+		s.mu.Unlock()
+		if err != nil {
+			msg := fmt.Sprintf("Invalid request from client: %s", err)
+			log.Println(msg)
+			s.conn.WriteJSON(&ResponseMessage{Code: 401, Message: msg})
+			continue
+		}
+		msg := ""
+		if result != game.ProblemOffTree {
+			msg = s.p.Comment()
+		}
+		s.conn.WriteJSON(&ResponseMessage{Code: 200, Message: msg, BStatus: s.p.Game.Rows(), Result: result.String()})
 	}
+}
+
+func (s *onlineSession) mainLoop() {
+	go s.onlinePlayerLoop(0, s.seats[0])
+}
+
+func (s *onlineSession) onlinePlayerLoop(seat int, con *websocket.Conn) {
+	player := s.player(seat)
+	pname := seat + 1
 	defer func() {
 		s.close(con)
 	}()
@@ -157,39 +426,143 @@ func (s *onlineSession) onlinePlayerLoop(black bool) {
 			if websocket.IsCloseError(err) || websocket.IsUnexpectedCloseError(err) {
 				return
 			}
-			msg := fmt.Sprintf("Error when reading input from client %s [%s]: %v", string(pname), s.id, err)
+			msg := fmt.Sprintf("Error when reading input from client %v [%s]: %v", pname, s.id, err)
 			log.Println(msg)
 			continue
 		}
 		if input.CloseConn {
-			log.Printf("Client %s [%s] request close session", string(pname), s.id)
+			log.Printf("Client %v [%s] request close session", pname, s.id)
 			return
 		}
-		if s.con1 == nil || s.con2 == nil {
-			msg := fmt.Sprintf("error in session %s: all players are not connected", s.id)
+		if input.Estimate && (s.phantom != nil || s.g.Colors > 0) {
+			msg := fmt.Sprintf("error in session %s: estimates are not available in Phantom Go or multi-color Go", s.id)
 			log.Println(msg)
 			con.WriteJSON(&ResponseMessage{Code: 401, Message: msg})
 			continue
 		}
+		if input.Estimate {
+			s.mu.Lock()
+			resp := newEstimateResponse(s.g)
+			s.mu.Unlock()
+			con.WriteJSON(resp)
+			continue
+		}
 		s.mu.Lock()
-		if err = s.g.Play(input.X, input.Y, black); err != nil {
+		if slices.Contains(s.seats, nil) {
 			s.mu.Unlock()
-			msg := fmt.Sprintf("Invalid request from client %s [%s]: %s", string(pname), s.id, err)
+			msg := fmt.Sprintf("error in session %s: all players are not connected", s.id)
 			log.Println(msg)
 			con.WriteJSON(&ResponseMessage{Code: 401, Message: msg})
 			continue
 		}
-        status := s.g.String()
+		if turn := s.rotation[s.moves%len(s.rotation)]; turn != seat {
+			s.mu.Unlock()
+			msg := fmt.Sprintf("Invalid request from client %v [%s]: invalid turn. now player %v must play", pname, s.id, turn+1)
+			log.Println(msg)
+			con.WriteJSON(&ResponseMessage{Code: 401, Message: msg})
+			continue
+		}
+		captured := 0
+		if s.phantom != nil {
+			captured, err = s.phantom.Play(input.X, input.Y, player == 0)
+		} else {
+			err = s.g.PlayColor(input.X, input.Y, player)
+		}
+		if err != nil {
+			status := ""
+			if s.phantom != nil {
+				status = s.view(player) //stones revealed by the attempt
+			}
+			s.mu.Unlock()
+			msg := fmt.Sprintf("Invalid request from client %v [%s]: %s", pname, s.id, err)
+			log.Println(msg)
+			con.WriteJSON(&ResponseMessage{Code: 401, Message: msg, BStatus: status})
+			continue
+		}
+		s.moves += 1
+		msg := ""
+		if captured > 0 { //referee announcement, the players can not see the captured stones
+			msg = fmt.Sprintf("%s captured %v stones", game.ColorName(player), captured)
+		}
+		winner, winnerMsg := captureWinner(s.g)
+		if winner != "" {
+			msg = winnerMsg
+		}
+		seats := slices.Clone(s.seats)
+		status := make([]string, len(seats))
+		for i := range seats {
+			status[i] = s.view(s.player(i))
+		}
 		s.mu.Unlock()
-        con.WriteJSON(&ResponseMessage{Code: 200, Message: "", BStatus: status})
-        if con == s.con1 {
-            s.con2.WriteJSON(&ResponseMessage{Code: 200, Message: "", BStatus: status})
-        } else {
-            s.con1.WriteJSON(&ResponseMessage{Code: 200, Message: "", BStatus: status})
-        }
+		for i, c := range seats {
+			c.WriteJSON(&ResponseMessage{Code: 200, Message: msg, BStatus: status[i], Winner: winner})
+		}
 	}
 }
 
+// player returns the player of the seat, from 0 (black), by its position in the rotation. The colors take turns
+// in order, so with two colors seats in even positions play black and in odd positions white.
+func (s *onlineSession) player(seat int) int {
+	return slices.Index(s.rotation, seat) % max(s.g.Colors, 2)
+}
+
+// maxSeats is the most players an online session can have: two for each color.
+const maxSeats = 10
+
+// newRotation validates the playing order of the seats of an online session. By default, one seat for each
+// color, or the seats in joining order if there are more.
+func newRotation(seats int, rotation []int, colors int) ([]int, error) {
+	if seats < 0 || seats > maxSeats || len(rotation) > maxSeats {
+		return nil, fmt.Errorf("invalid number of seats, must be up to %v", maxSeats)
+	}
+	if len(rotation) == 0 {
+		seats = max(seats, colors)
+		rotation = make([]int, seats)
+		for i := range rotation {
+			rotation[i] = i
+		}
+	}
+	if seats == 0 {
+		seats = len(rotation)
+	}
+	if len(rotation) != seats || seats%colors != 0 {
+		return nil, fmt.Errorf("invalid rotation %v: it must have every seat once, and the same number of seats for each color", rotation)
+	}
+	for i := range seats {
+		if !slices.Contains(rotation, i) {
+			return nil, fmt.Errorf("invalid rotation %v: it must have every seat once, and the same number of seats for each color", rotation)
+		}
+	}
+	return rotation, nil
+}
+
+// view returns the board status seen by the player. Only Phantom Go sessions hide the opponent stones.
+func (s *onlineSession) view(player int) string {
+	if s.phantom != nil {
+		return game.SplitRows(s.phantom.View(player == 0), s.g.Size())
+	}
+	return s.g.Rows()
+}
+
+// captureWinner returns the winner of a finished Capture Go game, "black" or "white", with a message announcing it.
+// Both are empty if the game goes on.
+func captureWinner(g *game.GoGame) (winner string, msg string) {
+	over, black := g.Winner()
+	if !over {
+		return "", ""
+	}
+	captures, winner := g.WhiteCaptures, game.ColorName(0)
+	if !black {
+		captures, winner = g.BlackCaptures, game.ColorName(1)
+	}
+	return winner, fmt.Sprintf("%s captured %v stones and wins", winner, captures)
+}
+
+func newEstimateResponse(g *game.GoGame) *EstimateResponseMessage {
+	e := g.Estimate(game.EstimateOptions{Komi: defaultKomi, Playouts: estimatePlayouts})
+	return &EstimateResponseMessage{Code: 200, Ownership: e.Ownership, Score: e.Score}
+}
+
 func (s *offlineSession) close(con *websocket.Conn) error {
 	var err error
 	log.Printf("Closing session %s", s.id)
@@ -204,30 +577,38 @@ func (s *offlineSession) close(con *websocket.Conn) error {
 	return nil
 }
 
+// close releases everything even if some part fails, so the engine subprocess never outlives the session.
+func (s *botSession) close(con *websocket.Conn) error {
+	log.Printf("Closing session %s", s.id)
+	return errors.Join(con.Close(), s.b.close(), s.g.Close())
+}
+
+func (s *problemSession) close(con *websocket.Conn) error {
+	log.Printf("Closing session %s", s.id)
+	if err := con.Close(); err != nil {
+		return err
+	}
+	return s.p.Game.Close()
+}
+
 func (s *onlineSession) close(con *websocket.Conn) error {
-	var err error
-	if s.con1 == con {
-		log.Printf("Closing client 1 of session %s", s.id)
-		err = s.con1.Close()
-		if err != nil {
-			return err
-		}
-		s.con1 = nil
-	} else {
-		log.Printf("Closing client 2 of session %s", s.id)
-		err = s.con2.Close()
-		if err != nil {
-			return err
-		}
-		s.con2 = nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seat := slices.Index(s.seats, con)
+	log.Printf("Closing client %v of session %s", seat+1, s.id)
+	err := con.Close()
+	if err != nil {
+		return err
 	}
-	if s.con1 == nil && s.con2 == nil {
-		log.Printf("Closing session %s", s.id)
-		err = s.g.Close()
-		if err != nil {
-			return err
-		}
-		s.m.CloseSession(s)
+	s.seats[seat] = nil
+	if slices.ContainsFunc(s.seats, func(c *websocket.Conn) bool { return c != nil }) {
+		return nil
+	}
+	log.Printf("Closing session %s", s.id)
+	err = s.g.Close()
+	if err != nil {
+		return err
 	}
+	s.m.CloseSession(s)
 	return nil
 }
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/n-bravo/go-in-go/game"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRotation(t *testing.T) {
+	assert := assert.New(t)
+	r, err := newRotation(0, nil, 2)
+	assert.NoError(err)
+	assert.Equal([]int{0, 1}, r)
+	r, err = newRotation(4, nil, 2)
+	assert.NoError(err)
+	assert.Equal([]int{0, 1, 2, 3}, r)
+	r, err = newRotation(0, []int{0, 3, 2, 1}, 2)
+	assert.NoError(err)
+	assert.Equal([]int{0, 3, 2, 1}, r)
+	for _, c := range []struct {
+		seats    int
+		rotation []int
+	}{{3, nil}, {4, []int{0, 1}}, {0, []int{0, 1, 1, 3}}, {0, []int{0, 1, 2, 4}}, {1e9, nil}, {-2, nil}, {0, make([]int, 12)}} {
+		_, err = newRotation(c.seats, c.rotation, 2)
+		assert.Error(err, c)
+	}
+
+	r, err = newRotation(0, nil, 3)
+	assert.NoError(err)
+	assert.Equal([]int{0, 1, 2}, r)
+	_, err = newRotation(4, nil, 3)
+	assert.Error(err)
+
+	g, _ := game.NewGame(5)
+	s := &onlineSession{rotation: []int{0, 3, 2, 1}, g: g}
+	assert.Equal(0, s.player(0))
+	assert.Equal(1, s.player(3))
+	assert.Equal(0, s.player(2))
+	assert.Equal(1, s.player(1))
+	g, _ = game.NewMultiColorGame(5, 3)
+	s = &onlineSession{rotation: []int{0, 1, 2, 3, 4, 5}, g: g}
+	assert.Equal(2, s.player(2))
+	assert.Equal(0, s.player(3))
+}
+
+// closingBot records whether it was closed, failing as requested.
+type closingBot struct {
+	closed bool
+	err    error
+}
+
+func (b *closingBot) play(x, y int, black bool) error     { return nil }
+func (b *closingBot) pass(black bool) error               { return nil }
+func (b *closingBot) genmove(black bool) (botMove, error) { return botMove{pass: true}, nil }
+func (b *closingBot) close() error {
+	b.closed = true
+	return b.err
+}
+
+func TestBotSessionCloseAlwaysClosesBot(t *testing.T) {
+	assert := assert.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c, err := (&websocket.Upgrader{}).Upgrade(w, r, nil); err == nil {
+			c.Close()
+		}
+	}))
+	defer srv.Close()
+	c, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	assert.NoError(err)
+	c.Close() //closing it again fails
+
+	g, _ := game.NewGame(5)
+	b := &closingBot{err: errors.New("engine crashed")}
+	s := &botSession{id: "test", conn: c, g: g, b: b}
+	err = s.close(c)
+	assert.True(b.closed)
+	assert.ErrorContains(err, "use of closed network connection")
+	assert.ErrorContains(err, "engine crashed")
+}
+
+// handshake sends the handshake message to a new server and returns its answer.
+func handshake(t *testing.T, h HandshakeSessionMessage) ResponseMessage {
+	srv := httptest.NewServer(WebSocketHandler{Origins: []string{"http://test"}})
+	defer srv.Close()
+	c, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), http.Header{"Origin": {"http://test"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	var r ResponseMessage
+	if err = c.WriteJSON(&h); err == nil {
+		err = c.ReadJSON(&r)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestHandshakeErrors(t *testing.T) {
+	assert := assert.New(t)
+	r := handshake(t, HandshakeSessionMessage{Size: 5, Online: true, Seats: 12})
+	assert.Equal(401, r.Code)
+	assert.Contains(r.Message, "invalid number of seats, must be up to 10")
+	r = handshake(t, HandshakeSessionMessage{Problem: "(;SZ[26];B[aa])"})
+	assert.Equal(401, r.Code)
+	assert.Contains(r.Message, "invalid problem: invalid board size")
+}